@@ -0,0 +1,192 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/openshift/api/apps/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"github.com/redhat-developer/devconsole-operator/pkg/resource"
+
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// defaultTargetCPUUtilization is the target of the autoscaler when the Component does not set any.
+const defaultTargetCPUUtilization = int32(80)
+
+// Annotations setting the bounds of the Knative autoscaler, which replaces the HorizontalPodAutoscaler for Components
+// deployed as Knative Services.
+const (
+	knativeMinScaleAnnotation = "autoscaling.knative.dev/minScale"
+	knativeMaxScaleAnnotation = "autoscaling.knative.dev/maxScale"
+)
+
+// isAutoscaled returns true when the replicas of the Component are set by an autoscaler.
+func isAutoscaled(cp *devconsoleapi.Component) bool {
+	return cp.Spec.Autoscaling != nil
+}
+
+// minReplicasOf returns the minimum number of replicas of an autoscaled Component, 1 by default.
+func minReplicasOf(cp *devconsoleapi.Component) int32 {
+	if cp.Spec.Autoscaling.MinReplicas != nil {
+		return *cp.Spec.Autoscaling.MinReplicas
+	}
+	return 1
+}
+
+// replicasOf returns the number of replicas the Component is deployed with: the minimum number of replicas of an
+// autoscaled Component, the replicas of its spec otherwise, 1 by default.
+func replicasOf(cp *devconsoleapi.Component) int32 {
+	if isAutoscaled(cp) {
+		return minReplicasOf(cp)
+	}
+	if cp.Spec.Replicas != nil {
+		return *cp.Spec.Replicas
+	}
+	return 1
+}
+
+// validateAutoscaling checks the bounds of the autoscaler of the Component.
+func validateAutoscaling(cp *devconsoleapi.Component) error {
+	if !isAutoscaled(cp) {
+		return nil
+	}
+	if cp.Spec.Autoscaling.MaxReplicas < 1 {
+		return fmt.Errorf("maximum replicas %d of the autoscaler must be at least 1", cp.Spec.Autoscaling.MaxReplicas)
+	}
+	if minReplicasOf(cp) < 1 || minReplicasOf(cp) > cp.Spec.Autoscaling.MaxReplicas {
+		return fmt.Errorf("minimum replicas %d of the autoscaler must be between 1 and %d", minReplicasOf(cp), cp.Spec.Autoscaling.MaxReplicas)
+	}
+	return nil
+}
+
+// newHorizontalPodAutoscaler returns the autoscaler of the Component, scaling its DeploymentConfig or its Deployment
+// on the CPU and memory utilization targets of its spec.
+func newHorizontalPodAutoscaler(cp *devconsoleapi.Component) *autoscalingv2beta1.HorizontalPodAutoscaler {
+	minReplicas := minReplicasOf(cp)
+	target := autoscalingv2beta1.CrossVersionObjectReference{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       DeploymentKindDeploymentConfig,
+		Name:       cp.Name,
+	}
+	if deploymentKindOf(cp) == DeploymentKindDeployment {
+		target.APIVersion = "apps/v1"
+		target.Kind = DeploymentKindDeployment
+	}
+	cpuTarget := cp.Spec.Autoscaling.TargetCPUUtilizationPercentage
+	memoryTarget := cp.Spec.Autoscaling.TargetMemoryUtilizationPercentage
+	if cpuTarget == nil && memoryTarget == nil {
+		defaultTarget := defaultTargetCPUUtilization
+		cpuTarget = &defaultTarget
+	}
+	var metrics []autoscalingv2beta1.MetricSpec
+	for _, metric := range []struct {
+		name   corev1.ResourceName
+		target *int32
+	}{{corev1.ResourceCPU, cpuTarget}, {corev1.ResourceMemory, memoryTarget}} {
+		if metric.target == nil {
+			continue
+		}
+		metrics = append(metrics, autoscalingv2beta1.MetricSpec{
+			Type: autoscalingv2beta1.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta1.ResourceMetricSource{
+				Name:                     metric.name,
+				TargetAverageUtilization: metric.target,
+			},
+		})
+	}
+	return &autoscalingv2beta1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cp.Name,
+			Namespace:   cp.Namespace,
+			Labels:      labelsForComponent(cp),
+			Annotations: resource.GetAnnotationsForCR(cp),
+		},
+		Spec: autoscalingv2beta1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: target,
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    cp.Spec.Autoscaling.MaxReplicas,
+			Metrics:        metrics,
+		},
+	}
+}
+
+// newKnativeScaleAnnotations returns the annotations bounding the Knative autoscaler of the Component.
+func newKnativeScaleAnnotations(cp *devconsoleapi.Component) map[string]string {
+	if !isAutoscaled(cp) {
+		return nil
+	}
+	return map[string]string{
+		knativeMinScaleAnnotation: strconv.Itoa(int(minReplicasOf(cp))),
+		knativeMaxScaleAnnotation: strconv.Itoa(int(cp.Spec.Autoscaling.MaxReplicas)),
+	}
+}
+
+// reconcileAutoscaler creates or updates the HorizontalPodAutoscaler of an autoscaled Component and deletes the one of
+// a Component which is not autoscaled anymore.
+func (r *ReconcileComponent) reconcileAutoscaler(cp *devconsoleapi.Component) error {
+	if !isAutoscaled(cp) {
+		return r.deleteControlled(cp, &autoscalingv2beta1.HorizontalPodAutoscaler{})
+	}
+	_, err := r.CreateHorizontalPodAutoscaler(cp)
+	return err
+}
+
+// CreateHorizontalPodAutoscaler creates or updates the HorizontalPodAutoscaler of the Component.
+func (r *ReconcileComponent) CreateHorizontalPodAutoscaler(cp *devconsoleapi.Component) (*autoscalingv2beta1.HorizontalPodAutoscaler, error) {
+	hpa := newHorizontalPodAutoscaler(cp)
+	if err := controllerutil.SetControllerReference(cp, hpa, r.scheme); err != nil {
+		log.Error(err, "** Setting owner reference fails **")
+		return nil, err
+	}
+	foundHpa := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: hpa.Name, Namespace: hpa.Namespace}, foundHpa)
+	if err == nil {
+		if !updateHorizontalPodAutoscaler(foundHpa, hpa) {
+			log.Info("** Skip Updating HorizontalPodAutoscaler: Already up to date", "HorizontalPodAutoscaler.Namespace", foundHpa.Namespace, "HorizontalPodAutoscaler.Name", foundHpa.Name)
+			return foundHpa, nil
+		}
+		log.Info("💡💡  Updating HorizontalPodAutoscaler 💡💡", "HorizontalPodAutoscaler.Namespace", foundHpa.Namespace, "HorizontalPodAutoscaler.Name", foundHpa.Name)
+		if err := r.client.Update(context.TODO(), foundHpa); err != nil {
+			log.Error(err, "** HorizontalPodAutoscaler update fails **")
+			return nil, err
+		}
+		return foundHpa, nil
+	}
+	if errors.IsNotFound(err) {
+		log.Info("💡💡  Creating a new HorizontalPodAutoscaler 💡💡", "HorizontalPodAutoscaler.Namespace", hpa.Namespace, "HorizontalPodAutoscaler.Name", hpa.Name)
+		err := r.client.Create(context.TODO(), hpa)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			log.Error(err, "** HorizontalPodAutoscaler creation fails **")
+			return nil, err
+		}
+		return hpa, nil
+	}
+	return nil, err
+}
+
+// desiredReplicas returns the number of replicas the deployment of the Component should have: the one computed by its
+// autoscaler, which may not be applied to the deployment yet, or else the replicas of the deployment.
+func (r *ReconcileComponent) desiredReplicas(cp *devconsoleapi.Component, replicas int32) int32 {
+	if !isAutoscaled(cp) {
+		return replicas
+	}
+	hpa := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: cp.Name, Namespace: cp.Namespace}, hpa); err != nil {
+		return replicas
+	}
+	if hpa.Status.DesiredReplicas > 0 {
+		return hpa.Status.DesiredReplicas
+	}
+	return replicas
+}
@@ -0,0 +1,125 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	buildv1 "github.com/openshift/api/build/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// buildNumber returns the number of the build in its BuildConfig, 0 when it is not annotated.
+func buildNumber(build *buildv1.Build) int64 {
+	number, _ := strconv.ParseInt(build.Annotations[buildv1.BuildNumberAnnotation], 10, 64)
+	return number
+}
+
+// latestBuild returns the build with the highest number, the most recently created one for builds without number.
+func latestBuild(builds []buildv1.Build) *buildv1.Build {
+	var latest *buildv1.Build
+	for i := range builds {
+		build := &builds[i]
+		if latest == nil || buildNumber(build) > buildNumber(latest) ||
+			(buildNumber(build) == buildNumber(latest) && latest.CreationTimestamp.Before(&build.CreationTimestamp)) {
+			latest = build
+		}
+	}
+	return latest
+}
+
+// GetLatestBuild returns the latest build of the Component's BuildConfig, or nil when none has been started.
+func (r *ReconcileComponent) GetLatestBuild(cp *devconsoleapi.Component) (*buildv1.Build, error) {
+	buildList := &buildv1.BuildList{}
+	opts := &client.ListOptions{
+		Namespace:     cp.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{buildv1.BuildConfigLabel: cp.Name}),
+	}
+	if err := r.client.List(context.TODO(), opts, buildList); err != nil {
+		log.Error(err, "failed to list existing Builds")
+		return nil, err
+	}
+	return latestBuild(buildList.Items), nil
+}
+
+// newBuildMapper returns the Component to reconcile when a Build changes, the one controlling the BuildConfig named by
+// the label of the Build. The Builds of the BuildConfigs which are not controlled by a Component are ignored.
+func newBuildMapper(cl client.Client) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		name := obj.Meta.GetLabels()[buildv1.BuildConfigLabel]
+		if name == "" {
+			return nil
+		}
+		bc := &buildv1.BuildConfig{}
+		if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: obj.Meta.GetNamespace(), Name: name}, bc); err != nil {
+			if !errors.IsNotFound(err) {
+				log.Error(err, "** failed to get the buildconfig of the build **")
+			}
+			return nil
+		}
+		if !isControlledByComponent(bc) {
+			return nil
+		}
+		return []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Namespace: bc.Namespace, Name: metav1.GetControllerOf(bc).Name},
+		}}
+	}
+}
+
+// isBuildFailed returns true when the build ended without building the image.
+func isBuildFailed(build *buildv1.Build) bool {
+	switch build.Status.Phase {
+	case buildv1.BuildPhaseFailed, buildv1.BuildPhaseError, buildv1.BuildPhaseCancelled:
+		return true
+	}
+	return false
+}
+
+// buildLogURL returns the URL of the log of the build on the API server.
+func buildLogURL(apiServerURL string, build *buildv1.Build) string {
+	return fmt.Sprintf("%s/apis/build.openshift.io/v1/namespaces/%s/builds/%s/log", strings.TrimSuffix(apiServerURL, "/"), build.Namespace, build.Name)
+}
+
+// newBuildStatus returns the details of the build reported in the status of the Component: its phase and times, the
+// commit it built, once cloned, and the digest of the image it pushed.
+func newBuildStatus(build *buildv1.Build, logURL string) *devconsoleapi.ComponentBuildStatus {
+	status := &devconsoleapi.ComponentBuildStatus{
+		Name:           build.Name,
+		Phase:          string(build.Status.Phase),
+		StartTime:      build.Status.StartTimestamp,
+		CompletionTime: build.Status.CompletionTimestamp,
+		LogURL:         logURL,
+	}
+	if revision := build.Spec.Revision; revision != nil && revision.Git != nil {
+		status.Commit = revision.Git.Commit
+		status.Author = revision.Git.Author.Name
+		status.Message = revision.Git.Message
+	}
+	if build.Status.Output.To != nil {
+		status.ImageDigest = build.Status.Output.To.ImageDigest
+	}
+	return status
+}
+
+// buildFailureMessage returns the message of the BuildSucceeded condition of a failed build, pointing to its log.
+func buildFailureMessage(build *buildv1.Build, logURL string) string {
+	reason := string(build.Status.Reason)
+	if reason == "" {
+		reason = string(build.Status.Phase)
+	}
+	if build.Status.Message != "" {
+		reason = fmt.Sprintf("%s: %s", reason, build.Status.Message)
+	}
+	return fmt.Sprintf("build %s failed, %s, see its log at %s", build.Name, reason, logURL)
+}
@@ -0,0 +1,116 @@
+package component
+
+import (
+	"path"
+
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Build strategies of a Component. When none is set in the spec, the docker strategy is chosen if the GitSourceAnalysis
+// detects a Dockerfile, the source strategy otherwise.
+const (
+	// BuildStrategySource builds the component with S2I from the builder image of its build type.
+	BuildStrategySource = "source"
+	// BuildStrategyDocker builds the component from the Dockerfile of its GitSource.
+	BuildStrategyDocker = "docker"
+	// BuildStrategyNone does not build the component, the images are pushed to its output image stream.
+	BuildStrategyNone = "none"
+)
+
+// dockerfileNames are the files selecting the docker build strategy when detected by the GitSourceAnalysis.
+var dockerfileNames = map[string]bool{"Dockerfile": true, "Containerfile": true}
+
+// buildStrategyOf returns the build strategy used for the Component: the one of its spec or, when detected, the one
+// recorded in its status.
+func buildStrategyOf(cp *devconsoleapi.Component) string {
+	if cp.Spec.BuildStrategy != "" {
+		return cp.Spec.BuildStrategy
+	}
+	if cp.Status.BuildStrategy != "" {
+		return cp.Status.BuildStrategy
+	}
+	return BuildStrategySource
+}
+
+// isValidBuildStrategy returns true when the build strategy of the spec is empty or one of the supported ones.
+func isValidBuildStrategy(cp *devconsoleapi.Component) bool {
+	switch cp.Spec.BuildStrategy {
+	case "", BuildStrategySource, BuildStrategyDocker, BuildStrategyNone:
+		return true
+	}
+	return false
+}
+
+// isDockerBuildType returns true when the build type detected by the GitSourceAnalysis is a Dockerfile.
+func isDockerBuildType(detected devconsoleapi.DetectedBuildType) bool {
+	if detected.Name == BuildStrategyDocker {
+		return true
+	}
+	for _, file := range detected.DetectedFiles {
+		if dockerfileNames[path.Base(file)] {
+			return true
+		}
+	}
+	return false
+}
+
+// newBuildStrategy returns the strategy of the Component's BuildConfig, building either from the builder image stream
+// or from the Dockerfile of the GitSource.
+func newBuildStrategy(cp *devconsoleapi.Component, builder *imagev1.ImageStream) buildv1.BuildStrategy {
+	if buildStrategyOf(cp) == BuildStrategyDocker {
+		return buildv1.BuildStrategy{
+			Type: buildv1.DockerBuildStrategyType,
+			DockerStrategy: &buildv1.DockerBuildStrategy{
+				DockerfilePath: cp.Spec.DockerfilePath,
+				BuildArgs:      cp.Spec.BuildArgs,
+				Env:            cp.Spec.BuildEnv,
+			},
+		}
+	}
+	incremental := true
+	return buildv1.BuildStrategy{
+		Type: buildv1.SourceBuildStrategyType,
+		SourceStrategy: &buildv1.SourceBuildStrategy{
+			From: corev1.ObjectReference{
+				Kind:      "ImageStreamTag",
+				Name:      builder.Name + ":" + builderTag(cp),
+				Namespace: builder.Namespace,
+			},
+			Incremental: &incremental,
+			Env:         cp.Spec.BuildEnv,
+		},
+	}
+}
+
+// newBuildTriggers returns the triggers of the Component's BuildConfig. The webhook trigger of the git provider of
+// the GitSource builds each push. The image change trigger follows the builder image, the docker strategy has none.
+func newBuildTriggers(cp *devconsoleapi.Component, gitSource *devconsoleapi.GitSource) []buildv1.BuildTriggerPolicy {
+	triggers := []buildv1.BuildTriggerPolicy{
+		{
+			Type: "ConfigChange",
+		},
+		newWebhookTrigger(cp, gitSource),
+	}
+	if buildStrategyOf(cp) == BuildStrategyDocker {
+		return triggers
+	}
+	return append(triggers, buildv1.BuildTriggerPolicy{
+		Type:        "ImageChange",
+		ImageChange: &buildv1.ImageChangeTrigger{},
+	})
+}
+
+// DeleteBuildConfig deletes the BuildConfig of a Component which is not built anymore, with its webhook.
+func (r *ReconcileComponent) DeleteBuildConfig(cp *devconsoleapi.Component) error {
+	cp.Status.LastBuild = nil
+	removeCondition(cp, ConditionBuildFailed)
+	if err := r.deleteWebhook(cp); err != nil {
+		return err
+	}
+	return r.deleteControlled(cp, &buildv1.BuildConfig{})
+}
@@ -0,0 +1,209 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// BuildTypeAuto lets the operator choose the build type of a Component from the GitSourceAnalysis of its GitSource.
+// An empty build type has the same meaning.
+const BuildTypeAuto = "auto"
+
+// isAutoBuildType returns true when the build type of the Component has to be detected.
+func isAutoBuildType(cp *devconsoleapi.Component) bool {
+	return cp.Spec.BuildType == "" || cp.Spec.BuildType == BuildTypeAuto
+}
+
+// buildTypeOf returns the build type used for the Component: the one of its spec or, when detected, the one recorded
+// in its status.
+func buildTypeOf(cp *devconsoleapi.Component) string {
+	if isAutoBuildType(cp) {
+		return cp.Status.BuildType
+	}
+	return cp.Spec.BuildType
+}
+
+// builderName returns the name of the builder image stream of the Component, its build type without the version.
+func builderName(cp *devconsoleapi.Component) string {
+	return strings.SplitN(buildTypeOf(cp), ":", 2)[0]
+}
+
+// builderTag returns the tag of the builder image stream used for the Component, the version following the build
+// type, like 10 in nodejs:10, or latest when no version is provided.
+func builderTag(cp *devconsoleapi.Component) string {
+	parts := strings.SplitN(buildTypeOf(cp), ":", 2)
+	if len(parts) < 2 || parts[1] == "" {
+		return "latest"
+	}
+	return parts[1]
+}
+
+// ResolveBuildType records in the Component status the build strategy and the build type to use. When the build type
+// is not provided in the spec, it is chosen among the build types detected by the GitSourceAnalysis of the GitSource:
+// the first one, in the ranking of the analysis, which has a builder image or, when no build strategy is set, which is
+// a Dockerfile. It returns false while the analysis is not done yet.
+func (r *ReconcileComponent) ResolveBuildType(cp *devconsoleapi.Component) (bool, error) {
+	if !isValidBuildStrategy(cp) {
+		err := fmt.Errorf("unknown build strategy %s, expected one of [%s, %s, %s]", cp.Spec.BuildStrategy, BuildStrategySource, BuildStrategyDocker, BuildStrategyNone)
+		setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionFalse, ReasonBuildStrategyNotSupported, err.Error())
+		return false, newPermanentError(err)
+	}
+	if cp.Spec.BuildStrategy == BuildStrategyDocker || cp.Spec.BuildStrategy == BuildStrategyNone {
+		cp.Status.BuildStrategy = cp.Spec.BuildStrategy
+		cp.Status.BuildType = ""
+		setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionTrue, ReasonBuildTypeNotRequired, fmt.Sprintf("build strategy %s does not use a builder image", cp.Spec.BuildStrategy))
+		return true, nil
+	}
+	if !isAutoBuildType(cp) {
+		cp.Status.BuildStrategy = BuildStrategySource
+		cp.Status.BuildType = cp.Spec.BuildType
+		setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionTrue, ReasonBuildTypeSpecified, fmt.Sprintf("build type %s set in the Component spec", cp.Spec.BuildType))
+		return true, nil
+	}
+	gsa, err := r.GetGitSourceAnalysis(cp)
+	if err != nil {
+		setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionFalse, ReasonAnalysisFailed, err.Error())
+		return false, err
+	}
+	if !gsa.Status.Analyzed {
+		log.Info(fmt.Sprintf("** Waiting for GitSourceAnalysis %s to detect the build type **", gsa.Name))
+		setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionUnknown, ReasonAnalysisPending, fmt.Sprintf("GitSourceAnalysis %s is not done yet", gsa.Name))
+		return false, nil
+	}
+	if gsa.Status.Error != "" {
+		err := fmt.Errorf("GitSourceAnalysis %s failed: %s", gsa.Name, gsa.Status.Error)
+		setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionFalse, ReasonAnalysisFailed, err.Error())
+		return false, newPermanentError(err)
+	}
+	var unsupported []string
+	for _, detected := range gsa.Status.BuildEnvStatistics.DetectedBuildTypes {
+		if cp.Spec.BuildStrategy == "" && isDockerBuildType(detected) {
+			log.Info(fmt.Sprintf("** Dockerfile detected by GitSourceAnalysis %s **", gsa.Name))
+			cp.Status.BuildStrategy = BuildStrategyDocker
+			cp.Status.BuildType = ""
+			setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionTrue, ReasonDockerfileDetected, fmt.Sprintf("Dockerfile detected by GitSourceAnalysis %s from files [%s], build strategy docker is used", gsa.Name, strings.Join(detected.DetectedFiles, ", ")))
+			return true, nil
+		}
+		supported, err := r.hasBuilderImage(cp, detected.Name)
+		if err != nil {
+			return false, err
+		}
+		if !supported {
+			unsupported = append(unsupported, detected.Name)
+			continue
+		}
+		log.Info(fmt.Sprintf("** Build type %s detected by GitSourceAnalysis %s **", detected.Name, gsa.Name))
+		cp.Status.BuildStrategy = BuildStrategySource
+		cp.Status.BuildType = detected.Name
+		message := fmt.Sprintf("build type %s detected by GitSourceAnalysis %s for language %s from files [%s]", detected.Name, gsa.Name, detected.Language, strings.Join(detected.DetectedFiles, ", "))
+		if len(unsupported) > 0 {
+			message += fmt.Sprintf(", higher ranked build types [%s] have no builder image", strings.Join(unsupported, ", "))
+		}
+		setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionTrue, ReasonBuildTypeDetected, message)
+		return true, nil
+	}
+	err = fmt.Errorf("none of the build types detected by GitSourceAnalysis %s has a builder image: [%s]", gsa.Name, strings.Join(unsupported, ", "))
+	setCondition(cp, ConditionBuildTypeResolved, corev1.ConditionFalse, ReasonBuildTypeNotSupported, err.Error())
+	return false, newPermanentError(err)
+}
+
+// hasBuilderImage returns true when the build type is provided by an image stream of the OpenShift namespace or by
+// the builder image catalog.
+func (r *ReconcileComponent) hasBuilderImage(cp *devconsoleapi.Component, buildType string) (bool, error) {
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: buildType, Namespace: openshiftNamespace}, &imagev1.ImageStream{})
+	if err == nil {
+		return true, nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, err
+	}
+	builder, err := r.findBuilderImage(cp.Namespace, buildType)
+	if err != nil {
+		return false, err
+	}
+	return builder != nil, nil
+}
+
+// GetGitSourceAnalysis returns a GitSourceAnalysis of the GitSource referenced by the Component, preferably one
+// already done. When there is none, a GitSourceAnalysis owned by the Component is created. The ones previously
+// created for another GitSource are deleted.
+func (r *ReconcileComponent) GetGitSourceAnalysis(cp *devconsoleapi.Component) (*devconsoleapi.GitSourceAnalysis, error) {
+	gsaList := &devconsoleapi.GitSourceAnalysisList{}
+	if err := r.client.List(context.TODO(), &client.ListOptions{Namespace: cp.Namespace}, gsaList); err != nil {
+		log.Error(err, "** failed to list gitsourceanalyses **")
+		return nil, err
+	}
+	var pending *devconsoleapi.GitSourceAnalysis
+	for i := range gsaList.Items {
+		gsa := &gsaList.Items[i]
+		if gsa.Spec.GitSourceRef.Name != cp.Spec.GitSourceRef {
+			if metav1.IsControlledBy(gsa, cp) {
+				log.Info("** Deleting GitSourceAnalysis of a previous GitSource", "GitSourceAnalysis.Namespace", gsa.Namespace, "GitSourceAnalysis.Name", gsa.Name)
+				if err := r.delete(gsa); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if gsa.Status.Analyzed {
+			return gsa, nil
+		}
+		pending = gsa
+	}
+	if pending != nil {
+		return pending, nil
+	}
+	gsa := newGitSourceAnalysis(cp)
+	if err := controllerutil.SetControllerReference(cp, gsa, r.scheme); err != nil {
+		log.Error(err, "** Setting owner reference fails **")
+		return nil, err
+	}
+	log.Info("💡💡 Creating a new GitSourceAnalysis 💡💡", "GitSourceAnalysis.Namespace", gsa.Namespace, "GitSourceAnalysis.Name", gsa.Name)
+	if err := r.client.Create(context.TODO(), gsa); err != nil && !errors.IsAlreadyExists(err) {
+		log.Error(err, "** GitSourceAnalysis creation fails **")
+		return nil, err
+	}
+	return gsa, nil
+}
+
+// newGitSourceAnalysisMapper returns a mapper which enqueues the Components detecting their build type from the
+// GitSource of the changed GitSourceAnalysis.
+func newGitSourceAnalysisMapper(cl client.Client) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		gsa, ok := obj.Object.(*devconsoleapi.GitSourceAnalysis)
+		if !ok {
+			return nil
+		}
+		cpList := &devconsoleapi.ComponentList{}
+		if err := cl.List(context.TODO(), &client.ListOptions{Namespace: gsa.Namespace}, cpList); err != nil {
+			log.Error(err, "** failed to list components using the gitsourceanalysis **")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, cp := range cpList.Items {
+			if !isAutoBuildType(&cp) || cp.Spec.GitSourceRef != gsa.Spec.GitSourceRef.Name {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cp.Namespace, Name: cp.Name},
+			})
+		}
+		return requests
+	}
+}
@@ -0,0 +1,166 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/yaml"
+)
+
+// builderCatalogName is the name of the ConfigMaps holding the builder image catalog. The catalog found in the
+// namespace of the operator applies to the whole cluster, the one found in the namespace of a Component extends and
+// overrides it for this namespace. Each key of the ConfigMap is a build type and its value describes the builder
+// image in YAML, for example:
+//
+//	python: |
+//	  image: centos/python-36-centos7:latest
+//	  versions:
+//	    "2.7": centos/python-27-centos7:latest
+//	    "3.6": centos/python-36-centos7:latest
+//	  ports:
+//	  - 8080
+//	  resources:
+//	    requests:
+//	      memory: 256Mi
+//	    limits:
+//	      memory: 512Mi
+const builderCatalogName = "devconsole-builder-images"
+
+// BuilderImage describes a builder image of the catalog.
+type BuilderImage struct {
+	// Image is the docker image used for the latest version of the builder.
+	Image string `json:"image"`
+	// Versions maps each version of the builder to its docker image. Optional.
+	Versions map[string]string `json:"versions,omitempty"`
+	// Ports exposed by default by the applications built with this builder. Optional, the ports exposed by the
+	// builder image are used otherwise.
+	Ports []int32 `json:"ports,omitempty"`
+	// Resources are the default requests and limits of the containers of the applications built with this builder,
+	// used when the Component does not set its own. Optional.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// defaultBuilderImages is used when none of the catalogs provides the build type.
+var defaultBuilderImages = map[string]BuilderImage{
+	"nodejs": {Image: "nodeshift/centos7-s2i-nodejs:10.x"},
+}
+
+// sortedVersions returns the versions of the builder image sorted by name.
+func (b *BuilderImage) sortedVersions() []string {
+	versions := make([]string, 0, len(b.Versions))
+	for version := range b.Versions {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// containerPorts returns the default ports of the builder image as container ports.
+func (b *BuilderImage) containerPorts() []corev1.ContainerPort {
+	var ports []corev1.ContainerPort
+	for _, port := range b.Ports {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          fmt.Sprintf("%d-tcp", port),
+			ContainerPort: port,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
+// GetBuilderImage looks up the builder image of the Component's build type in the catalog of the Component's
+// namespace, then in the cluster-wide catalog and finally in the default builder images. It returns nil when the
+// build type is unknown.
+func (r *ReconcileComponent) GetBuilderImage(cp *devconsoleapi.Component) (*BuilderImage, error) {
+	return r.findBuilderImage(cp.Namespace, builderName(cp))
+}
+
+func (r *ReconcileComponent) findBuilderImage(cpNamespace, buildType string) (*BuilderImage, error) {
+	namespaces := []string{cpNamespace}
+	if r.operatorNamespace != "" && r.operatorNamespace != cpNamespace {
+		namespaces = append(namespaces, r.operatorNamespace)
+	}
+	for _, namespace := range namespaces {
+		catalog, err := r.getBuilderCatalog(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if builder, ok := catalog[buildType]; ok {
+			log.Info(fmt.Sprintf("** Found builder image for build type %s in catalog %s/%s **", buildType, namespace, builderCatalogName))
+			return &builder, nil
+		}
+	}
+	if builder, ok := defaultBuilderImages[buildType]; ok {
+		return &builder, nil
+	}
+	return nil, nil
+}
+
+func (r *ReconcileComponent) getBuilderCatalog(namespace string) (map[string]BuilderImage, error) {
+	cm := &corev1.ConfigMap{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: builderCatalogName}, cm)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		log.Error(err, "** failed to get builder image catalog **")
+		return nil, err
+	}
+	return parseBuilderCatalog(cm), nil
+}
+
+// parseBuilderCatalog reads the builder images of the catalog ConfigMap. Invalid entries are logged and skipped so
+// that a single mistake in the catalog does not break the other build types.
+func parseBuilderCatalog(cm *corev1.ConfigMap) map[string]BuilderImage {
+	catalog := make(map[string]BuilderImage)
+	for buildType, data := range cm.Data {
+		builder := BuilderImage{}
+		if err := yaml.Unmarshal([]byte(data), &builder); err != nil {
+			log.Error(err, fmt.Sprintf("** invalid builder image %s in catalog %s/%s **", buildType, cm.Namespace, cm.Name))
+			continue
+		}
+		if builder.Image == "" {
+			log.Info(fmt.Sprintf("** Skip builder image %s in catalog %s/%s: no image provided **", buildType, cm.Namespace, cm.Name))
+			continue
+		}
+		catalog[buildType] = builder
+	}
+	return catalog
+}
+
+// newBuilderCatalogMapper returns a mapper which enqueues the Components affected by a change of a catalog: all of
+// them for the cluster-wide catalog, the ones of the namespace otherwise.
+func newBuilderCatalogMapper(cl client.Client, operatorNamespace string) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		if obj.Meta.GetName() != builderCatalogName {
+			return nil
+		}
+		opts := &client.ListOptions{}
+		if obj.Meta.GetNamespace() != operatorNamespace {
+			opts.Namespace = obj.Meta.GetNamespace()
+		}
+		cpList := &devconsoleapi.ComponentList{}
+		if err := cl.List(context.TODO(), opts, cpList); err != nil {
+			log.Error(err, "** failed to list components using the builder image catalog **")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, cp := range cpList.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cp.Namespace, Name: cp.Name},
+			})
+		}
+		return requests
+	}
+}
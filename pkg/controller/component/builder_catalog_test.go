@@ -0,0 +1,94 @@
+package component
+
+import (
+	"testing"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newBuilderCatalog(namespace string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      builderCatalogName,
+			Namespace: namespace,
+		},
+		Data: data,
+	}
+}
+
+func TestParseBuilderCatalog(t *testing.T) {
+	catalog := parseBuilderCatalog(newBuilderCatalog(Namespace, map[string]string{
+		"python": `
+image: centos/python-36-centos7:latest
+versions:
+  "2.7": centos/python-27-centos7:latest
+  "3.6": centos/python-36-centos7:latest
+ports:
+- 8080
+`,
+		"ruby":    "versions: {}",
+		"invalid": "image: [",
+	}))
+
+	require.Equal(t, 1, len(catalog), "invalid builder images should be skipped")
+	python := catalog["python"]
+	require.Equal(t, "centos/python-36-centos7:latest", python.Image)
+	require.Equal(t, []string{"2.7", "3.6"}, python.sortedVersions())
+	require.Equal(t, "centos/python-27-centos7:latest", python.Versions["2.7"])
+	require.Equal(t, []int32{8080}, python.Ports)
+	ports := python.containerPorts()
+	require.Equal(t, 1, len(ports))
+	require.Equal(t, "8080-tcp", ports[0].Name)
+	require.Equal(t, corev1.ProtocolTCP, ports[0].Protocol)
+}
+
+func TestGetBuilderImage(t *testing.T) {
+	operatorNamespace := "devconsole"
+	clusterCatalog := newBuilderCatalog(operatorNamespace, map[string]string{
+		"python": "image: centos/python-36-centos7:latest",
+		"ruby":   "image: centos/ruby-25-centos7:latest",
+	})
+	namespaceCatalog := newBuilderCatalog(Namespace, map[string]string{
+		"python": "image: registry.corp/python-36:latest",
+	})
+	cl := fake.NewFakeClient(clusterCatalog, namespaceCatalog)
+	r := &ReconcileComponent{client: cl, operatorNamespace: operatorNamespace}
+	newComponent := func(buildType string) *devconsoleapi.Component {
+		return &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{Name: Name, Namespace: Namespace},
+			Spec:       devconsoleapi.ComponentSpec{BuildType: buildType},
+		}
+	}
+
+	t.Run("namespace catalog overrides cluster catalog", func(t *testing.T) {
+		builder, err := r.GetBuilderImage(newComponent("python"))
+		require.NoError(t, err)
+		require.Equal(t, "registry.corp/python-36:latest", builder.Image)
+	})
+
+	t.Run("cluster catalog", func(t *testing.T) {
+		builder, err := r.GetBuilderImage(newComponent("ruby"))
+		require.NoError(t, err)
+		require.Equal(t, "centos/ruby-25-centos7:latest", builder.Image)
+	})
+
+	t.Run("default builder images", func(t *testing.T) {
+		builder, err := r.GetBuilderImage(newComponent("nodejs"))
+		require.NoError(t, err)
+		require.Equal(t, "nodeshift/centos7-s2i-nodejs:10.x", builder.Image)
+	})
+
+	t.Run("unknown build type", func(t *testing.T) {
+		builder, err := r.GetBuilderImage(newComponent("cobol"))
+		require.NoError(t, err)
+		require.Nil(t, builder)
+	})
+}
@@ -8,15 +8,22 @@ import (
 	buildv1 "github.com/openshift/api/build/v1"
 	imagev1 "github.com/openshift/api/image/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	buildclientset "github.com/openshift/client-go/build/clientset/versioned/typed/build/v1"
 	imageclientset "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1"
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -25,6 +32,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sort"
+	"strings"
 )
 
 var log = logf.Log
@@ -36,46 +45,159 @@ func Add(mgr manager.Manager) error {
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager) *ReconcileComponent {
 	config := mgr.GetConfig()
 	cl, _ := imageclientset.NewForConfig(config)
-	return &ReconcileComponent{client: mgr.GetClient(), scheme: mgr.GetScheme(), imageClient: cl}
+	buildClient, _ := buildclientset.NewForConfig(config)
+	operatorNamespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		log.Info(fmt.Sprintf("** Operator namespace not found, the cluster-wide builder image catalog is not used: %s **", err))
+	}
+	// the URL of the API server seen by the operator may not be reachable from the git providers
+	apiServerURL := os.Getenv(apiServerURLEnvVar)
+	if apiServerURL == "" {
+		apiServerURL = config.Host
+	}
+	return &ReconcileComponent{client: mgr.GetClient(), scheme: mgr.GetScheme(), restMapper: mgr.GetRESTMapper(), imageClient: cl, buildClient: buildClient, operatorNamespace: operatorNamespace, apiServerURL: apiServerURL}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r *ReconcileComponent) error {
 	// Create a new controller
 	c, err := controller.New("component-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to primary resource Component
-	err = c.Watch(&source.Kind{Type: &devconsoleapi.Component{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to primary resource Component, ignoring the updates of its status
+	err = c.Watch(&source.Kind{Type: &devconsoleapi.Component{}}, &handler.EnqueueRequestForObject{}, ignoreStatusUpdates)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource DeploymentConfig controlled by a Component
+	err = c.Watch(&source.Kind{Type: &v1.DeploymentConfig{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}, componentControlledChanges)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource Deployment controlled by a Component
+	err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}, componentControlledChanges)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource PersistentVolumeClaim, so that a deleted claim is created again
+	err = c.Watch(&source.Kind{Type: &corev1.PersistentVolumeClaim{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}, componentControlledChanges)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource HorizontalPodAutoscaler, the replicas it sets tell when the component is
+	// stable
+	err = c.Watch(&source.Kind{Type: &autoscalingv2beta1.HorizontalPodAutoscaler{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}, componentControlledChanges)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource BuildConfig controlled by a Component
+	err = c.Watch(&source.Kind{Type: &buildv1.BuildConfig{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}, componentControlledChanges)
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to secondary resource DeploymentConfig
-	err = c.Watch(&source.Kind{Type: &v1.DeploymentConfig{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to the Builds of the BuildConfigs controlled by a Component
+	err = c.Watch(&source.Kind{Type: &buildv1.Build{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: newBuildMapper(mgr.GetClient()),
+	}, buildChanges)
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to secondary resource BuildConfig
-	err = c.Watch(&source.Kind{Type: &buildv1.BuildConfig{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to secondary resource Service controlled by a Component
+	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}, componentControlledChanges)
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to secondary resource Service
-	err = c.Watch(&source.Kind{Type: &corev1.Service{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to secondary resource Route controlled by a Component
+	err = c.Watch(&source.Kind{Type: &routev1.Route{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}, componentControlledChanges)
 	if err != nil {
 		return err
 	}
 
-	// Watch for changes to secondary resource Route
-	err = c.Watch(&source.Kind{Type: &routev1.Route{}}, &handler.EnqueueRequestForObject{})
+	// Watch for changes to secondary resource Knative Service, only when Knative Serving is installed
+	if _, err := mgr.GetRESTMapper().RESTMapping(knativeServiceGVK.GroupKind(), knativeServiceGVK.Version); err != nil {
+		log.Info(fmt.Sprintf("** Knative Serving not found, components cannot be deployed as Knative Services: %s **", err))
+	} else {
+		err = c.Watch(&source.Kind{Type: newKnativeService()}, &handler.EnqueueRequestForOwner{
+			IsController: true,
+			OwnerType:    &devconsoleapi.Component{},
+		}, componentControlledChanges)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Watch for changes to secondary resource ImageStream, importing the image of image-only components or the builder
+	// images shared by several components, which own them without controlling them
+	err = c.Watch(&source.Kind{Type: &imagev1.ImageStream{}}, &handler.EnqueueRequestForOwner{
+		OwnerType: &devconsoleapi.Component{},
+	}, componentOwnedChanges)
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the GitSourceAnalysis detecting the build type of components
+	err = c.Watch(&source.Kind{Type: &devconsoleapi.GitSourceAnalysis{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: newGitSourceAnalysisMapper(mgr.GetClient()),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the Secrets holding the certificates of the routes, used to clone the GitSources or
+	// referenced by the environment of components
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: newSecretMapper(mgr.GetClient()),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the ConfigMaps referenced by the environment of components
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: newEnvSourceMapper(mgr.GetClient()),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the builder image catalogs
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: newBuilderCatalogMapper(mgr.GetClient(), r.operatorNamespace),
+	})
 	if err != nil {
 		return err
 	}
@@ -84,7 +206,6 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 var (
 	_                  reconcile.Reconciler = &ReconcileComponent{}
-	buildTypeImages                         = map[string]string{"nodejs": "nodeshift/centos7-s2i-nodejs:10.x"}
 	openshiftNamespace                      = "openshift"
 )
 
@@ -94,7 +215,15 @@ type ReconcileComponent struct {
 	// that reads objects from the cache and writes to the apiserver
 	client      client.Client
 	imageClient imageclientset.ImageV1Interface
+	// buildClient starts the builds requested on demand, through the instantiate subresource of the BuildConfigs
+	buildClient buildclientset.BuildV1Interface
 	scheme      *runtime.Scheme
+	// restMapper tells whether the optional APIs, such as the Knative Services, are served by the cluster
+	restMapper meta.RESTMapper
+	// operatorNamespace holds the cluster-wide builder image catalog
+	operatorNamespace string
+	// apiServerURL is the public URL of the API server, published in the webhook URLs of the Components
+	apiServerURL string
 }
 
 // Reconcile reads that state of the cluster for a Component object and makes changes based on the state read
@@ -117,16 +246,32 @@ func (r *ReconcileComponent) Reconcile(request reconcile.Request) (reconcile.Res
 		return reconcile.Result{}, err
 	}
 
+	if !cp.ObjectMeta.DeletionTimestamp.IsZero() {
+		log.Info("👻👻 Deleting component CR 👻👻")
+		return reconcile.Result{}, r.Finalize(cp)
+	}
+	if err := r.AddFinalizer(cp); err != nil {
+		return reconcile.Result{}, err
+	}
+	status := cp.Status.DeepCopy()
+
 	// Checking and logging secondary resource lifecycle
-	dcList := &v1.DeploymentConfigList{}
-	err = r.ObserveDeploymentConfig(cp, dcList)
+	switch deploymentKindOf(cp) {
+	case DeploymentKindKnativeService:
+		err = r.ObserveKnativeService(cp)
+	case DeploymentKindDeployment:
+		err = r.ObserveDeployment(cp, &appsv1.DeploymentList{})
+	default:
+		err = r.ObserveDeploymentConfig(cp, &v1.DeploymentConfigList{})
+	}
 	if err != nil {
-		return reconcile.Result{}, nil
+		// requeued with backoff
+		return reconcile.Result{}, err
 	}
 	bcList := &buildv1.BuildConfigList{}
 	err = r.ObserveBuildConfig(cp, bcList)
 	if err != nil {
-		return reconcile.Result{}, nil
+		return reconcile.Result{}, err
 	}
 
 	log.Info("============================================================")
@@ -142,55 +287,204 @@ func (r *ReconcileComponent) Reconcile(request reconcile.Request) (reconcile.Res
 		cp.Status.RevNumber = cp.ObjectMeta.ResourceVersion
 	}
 
-	if !cp.ObjectMeta.DeletionTimestamp.IsZero() {
-		log.Info("👻👻 Deleting component CR 👻👻")
+	route, err := r.reconcileResources(cp)
+	created := err == nil && cp.Status.RevNumber == cp.ObjectMeta.ResourceVersion
+	updateReadyCondition(cp)
+	if updateErr := r.UpdateStatus(cp, status); updateErr != nil {
+		return reconcile.Result{}, updateErr
+	}
+	if isPermanentError(err) {
+		// reported by the conditions, the Component is reconciled again once it changes
+		log.Info(fmt.Sprintf("** Component %s cannot be reconciled: %s **", cp.Name, err))
 		return reconcile.Result{}, nil
 	}
-
-	gitSource, err := r.GetGitSource(cp)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
-	outputIS, err := r.CreateOutputImageStream(cp)
+	if created {
+		log.Info(fmt.Sprintf("🎉🎉  Component %s has been successfully created!  🎉🎉 ", cp.Name))
+		if route != nil {
+			scheme := "http"
+			if route.Spec.TLS != nil {
+				scheme = "https"
+			}
+			log.Info(fmt.Sprintf("🎉🎉  Go to %s://%s%s  🎉🎉 ", scheme, route.Spec.Host, route.Spec.Path))
+		}
+	}
+
+	return reconcile.Result{}, nil
+}
+
+// reconcileResources creates or updates the resources of the Component, each step setting its own condition.
+func (r *ReconcileComponent) reconcileResources(cp *devconsoleapi.Component) (*routev1.Route, error) {
+	if err := validatePort(cp); err != nil {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonPortInvalid, err.Error())
+		return nil, newPermanentError(err)
+	}
+	var outputIS *imagev1.ImageStream
+	var ports []corev1.ContainerPort
+	var err error
+	if isImageComponent(cp) {
+		outputIS, ports, err = r.reconcileImage(cp)
+	} else {
+		outputIS, ports, err = r.reconcileBuild(cp)
+	}
+	if err != nil || outputIS == nil {
+		return nil, err
+	}
+	if deploymentKindOf(cp) == DeploymentKindKnativeService {
+		return nil, r.reconcileKnativeService(cp, outputIS, ports)
+	}
+	err = r.reconcileDeployment(cp, outputIS, ports)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
-	builderIS, err := r.CreateBuilderImageStream(cp)
+	_, err = r.CreateService(cp, ports)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
+	}
+	cp.Status.URL = ""
+	if !cp.Spec.Exposed {
+		removeCondition(cp, ConditionRouteAdmitted)
+		return nil, nil
+	}
+	port := primaryPort(cp, ports)
+	if port == nil {
+		setCondition(cp, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonNoRoutablePort, "the component does not expose any TCP port")
+		return nil, r.deleteControlled(cp, &routev1.Route{})
+	}
+	if err := validateRouteHost(cp); err != nil {
+		setCondition(cp, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonRouteConfigInvalid, err.Error())
+		return nil, nil
 	}
-	secret, _ := r.GetSourceSecret(cp, gitSource)
-	_, err = r.CreateBuildConfig(cp, builderIS, gitSource, secret)
+	tls, ok, err := r.GetRouteTLS(cp)
+	if err != nil || !ok {
+		return nil, err
+	}
+	conflict, err := r.findConflictingRoute(cp, newRoute(cp, port, tls))
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
-	ports, err := r.GetExposedPorts(cp, "latest", builderIS)
+	if conflict != nil {
+		log.Info(fmt.Sprintf("** Host %s%s is already claimed by Route %s **", cp.Spec.Host, cp.Spec.Path, conflict.Name))
+		setCondition(cp, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonRouteHostConflict, fmt.Sprintf("host %s%s is already claimed by Route %s", cp.Spec.Host, cp.Spec.Path, conflict.Name))
+		return nil, nil
+	}
+	route, err := r.CreateRoute(cp, port, tls)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, err
 	}
-	_, err = r.CreateDeploymentConfig(cp, outputIS, ports)
+	r.ObserveRoute(cp, route)
+	return route, nil
+}
+
+// reconcileBuild creates or updates the output image stream and the BuildConfig of a Component built from its
+// GitSource. It returns the output image stream and the exposed ports, or no image stream while the build type is
+// being detected.
+func (r *ReconcileComponent) reconcileBuild(cp *devconsoleapi.Component) (*imagev1.ImageStream, []corev1.ContainerPort, error) {
+	gitSource, err := r.GetGitSource(cp)
 	if err != nil {
-		return reconcile.Result{}, err
+		setCondition(cp, ConditionSourceResolved, corev1.ConditionFalse, ReasonGitSourceNotFound, err.Error())
+		return nil, nil, err
 	}
-	_, err = r.CreateService(cp, ports)
+	setCondition(cp, ConditionSourceResolved, corev1.ConditionTrue, ReasonGitSourceFound, fmt.Sprintf("GitSource %s found", gitSource.Name))
+	outputIS, err := r.CreateOutputImageStream(cp)
 	if err != nil {
-		return reconcile.Result{}, err
+		return nil, nil, err
 	}
-	var route *routev1.Route
-	if cp.Spec.Exposed {
-		route, err = r.CreateRoute(cp)
-		if err != nil {
-			return reconcile.Result{}, err
-		}
+	resolved, err := r.ResolveBuildType(cp)
+	if err != nil || !resolved {
+		return nil, nil, err
 	}
-	if cp.Status.RevNumber == cp.ObjectMeta.ResourceVersion {
-		log.Info(fmt.Sprintf("🎉🎉  Component %s has been successfully created!  🎉🎉 ", cp.Name))
-		if route != nil {
-			log.Info(fmt.Sprintf("🎉🎉  Go to http://%s:%d  🎉🎉 ", route.Spec.Host, route.Spec.Port.TargetPort.IntVal))
+	var ports []corev1.ContainerPort
+	switch buildStrategyOf(cp) {
+	case BuildStrategyNone:
+		removeCondition(cp, ConditionBuilderImageReady)
+		removeCondition(cp, ConditionBuildSucceeded)
+		if err := r.DeleteBuildConfig(cp); err != nil {
+			return nil, nil, err
+		}
+		ports, err = r.GetExposedPorts(cp, "", nil, nil)
+	case BuildStrategyDocker:
+		removeCondition(cp, ConditionBuilderImageReady)
+		var secret *corev1.Secret
+		if secret, err = r.GetSourceSecret(cp, gitSource); err != nil {
+			return nil, nil, err
+		}
+		var bc *buildv1.BuildConfig
+		if bc, err = r.CreateBuildConfig(cp, nil, gitSource, secret); err != nil {
+			return nil, nil, err
+		}
+		if err := r.reconcileWebhook(cp, gitSource); err != nil {
+			return nil, nil, err
 		}
+		if err := r.reconcileRebuild(cp, bc); err != nil {
+			return nil, nil, err
+		}
+		ports, err = r.GetExposedPorts(cp, "", nil, nil)
+	default:
+		var detected bool
+		ports, detected, err = r.reconcileSourceBuild(cp, gitSource)
+		if err == nil && !detected {
+			return nil, nil, nil
+		}
+	}
+	if err != nil {
+		return nil, nil, err
 	}
+	return outputIS, ports, nil
+}
 
-	return reconcile.Result{}, nil
+// reconcileSourceBuild creates or updates the builder image stream and the S2I BuildConfig of the Component. It
+// returns the ports exposed by the builder image. While the tag of the builder image is being imported, the build is
+// started by the image change trigger of the BuildConfig once it is done, and it returns false when the ports have to be
+// read from this image: the Component is reconciled again when the builder image stream changes.
+func (r *ReconcileComponent) reconcileSourceBuild(cp *devconsoleapi.Component, gitSource *devconsoleapi.GitSource) ([]corev1.ContainerPort, bool, error) {
+	builder, err := r.GetBuilderImage(cp)
+	if err != nil {
+		return nil, false, err
+	}
+	builderIS, err := r.CreateBuilderImageStream(cp, builder)
+	if err != nil {
+		setCondition(cp, ConditionBuilderImageReady, corev1.ConditionFalse, ReasonBuilderImageNotFound, err.Error())
+		return nil, false, err
+	}
+	tag := builderTag(cp)
+	imported := isImageStreamTagImported(builderIS, tag)
+	switch {
+	case imported:
+		setCondition(cp, ConditionBuilderImageReady, corev1.ConditionTrue, ReasonBuilderImageFound, fmt.Sprintf("builder image %s:%s found", builderIS.Name, tag))
+	case imageStreamTagImportFailure(builderIS, tag) != "":
+		err := fmt.Errorf("import of tag %s of builder ImageStream %s/%s failed: %s", tag, builderIS.Namespace, builderIS.Name, imageStreamTagImportFailure(builderIS, tag))
+		setCondition(cp, ConditionBuilderImageReady, corev1.ConditionFalse, ReasonImageImportFailed, err.Error())
+		return nil, false, newPermanentError(err)
+	case hasImageStreamTag(builderIS, tag):
+		log.Info(fmt.Sprintf("** Waiting for the import of tag %s of builder ImageStream %s/%s **", tag, builderIS.Namespace, builderIS.Name))
+		setCondition(cp, ConditionBuilderImageReady, corev1.ConditionUnknown, ReasonBuilderImageImportPending, fmt.Sprintf("tag %s of builder ImageStream %s/%s is being imported", tag, builderIS.Namespace, builderIS.Name))
+	default:
+		err := fmt.Errorf("tag %s not found in builder ImageStream %s/%s, available tags: [%s]", tag, builderIS.Namespace, builderIS.Name, strings.Join(imageStreamTags(builderIS), ", "))
+		setCondition(cp, ConditionBuilderImageReady, corev1.ConditionFalse, ReasonBuilderTagNotFound, err.Error())
+		return nil, false, err
+	}
+	secret, err := r.GetSourceSecret(cp, gitSource)
+	if err != nil {
+		return nil, false, err
+	}
+	bc, err := r.CreateBuildConfig(cp, builderIS, gitSource, secret)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := r.reconcileWebhook(cp, gitSource); err != nil {
+		return nil, false, err
+	}
+	if err := r.reconcileRebuild(cp, bc); err != nil {
+		return nil, false, err
+	}
+	if !imported && cp.Spec.Port == 0 && (builder == nil || len(builder.Ports) == 0) {
+		return nil, false, nil
+	}
+	ports, err := r.GetExposedPorts(cp, tag, builderIS, builder)
+	return ports, err == nil, err
 }
 
 // ObserveBuildConfig watches for secondary resource BuildConfig.
@@ -210,12 +504,50 @@ func (r *ReconcileComponent) ObserveBuildConfig(cp *devconsoleapi.Component, bcL
 		return err
 	}
 
+	if len(bcList.Items) == 0 {
+		setCondition(cp, ConditionBuildSucceeded, corev1.ConditionUnknown, ReasonBuildPending, "BuildConfig is not created yet")
+		return nil
+	}
 	for _, bc := range bcList.Items {
 		if bc.Status.LastVersion == 0 {
 			log.Info(fmt.Sprintf("👻👻  Scaling down BuildConfig %s 👻👻", bc.Name))
-			return r.UpdateStatus(cp, devconsoleapi.PhaseBuilding)
+			cp.Status.Phase = devconsoleapi.PhaseBuilding
+			setCondition(cp, ConditionBuildSucceeded, corev1.ConditionUnknown, ReasonBuildPending, fmt.Sprintf("no build started yet for BuildConfig %s", bc.Name))
+			return nil
+		}
+	}
+
+	build, err := r.GetLatestBuild(cp)
+	if err != nil {
+		return err
+	}
+	if build != nil {
+		logURL := buildLogURL(r.apiServerURL, build)
+		cp.Status.LastBuild = newBuildStatus(build, logURL)
+		if isBuildFailed(build) {
+			log.Info(fmt.Sprintf("👻👻  Build %s failed 👻👻", build.Name))
+			message := buildFailureMessage(build, logURL)
+			setCondition(cp, ConditionBuildFailed, corev1.ConditionTrue, ReasonBuildFailed, message)
+			setCondition(cp, ConditionBuildSucceeded, corev1.ConditionFalse, ReasonBuildFailed, message)
+			return nil
+		}
+		setCondition(cp, ConditionBuildFailed, corev1.ConditionFalse, ReasonBuildNotFailed, fmt.Sprintf("build %s is %s", build.Name, build.Status.Phase))
+	}
+
+	// a build succeeded once the output image stream has an image
+	outputIS := &imagev1.ImageStream{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: cp.Name, Namespace: cp.Namespace}, outputIS)
+	if err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "failed to get output ImageStream")
+		return err
+	}
+	for _, tag := range outputIS.Status.Tags {
+		if tag.Tag == "latest" && len(tag.Items) > 0 {
+			setCondition(cp, ConditionBuildSucceeded, corev1.ConditionTrue, ReasonImageBuilt, fmt.Sprintf("image %s:latest has been built", cp.Name))
+			return nil
 		}
 	}
+	setCondition(cp, ConditionBuildSucceeded, corev1.ConditionUnknown, ReasonBuildRunning, fmt.Sprintf("image %s:latest is being built", cp.Name))
 	return nil
 }
 
@@ -236,32 +568,86 @@ func (r *ReconcileComponent) ObserveDeploymentConfig(cp *devconsoleapi.Component
 		return err
 	}
 
+	if len(dcList.Items) == 0 {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionUnknown, ReasonDeploymentPending, "DeploymentConfig is not created yet")
+		return nil
+	}
+	phase := devconsoleapi.PhaseDeployed
+	var unavailable []string
 	for _, dc := range dcList.Items {
-		if dc.Status.Replicas < dc.Spec.Replicas {
+		// an autoscaled DeploymentConfig is stable once it runs the replicas computed by its autoscaler
+		desired := r.desiredReplicas(cp, dc.Spec.Replicas)
+		if dc.Status.Replicas < desired {
 			log.Info(fmt.Sprintf("👻👻  Scaling up DeploymentConfig %s 👻👻", dc.Name))
-			return r.UpdateStatus(cp, devconsoleapi.PhaseDeploying)
+			phase = devconsoleapi.PhaseDeploying
 		} else {
 			log.Info(fmt.Sprintf("✨✨ Stable DeploymentConfig %s ✨✨", dc.Name))
-			return r.UpdateStatus(cp, devconsoleapi.PhaseDeployed)
 		}
+		if dc.Status.AvailableReplicas < desired {
+			unavailable = append(unavailable, fmt.Sprintf("DeploymentConfig %s has %d/%d available replicas", dc.Name, dc.Status.AvailableReplicas, desired))
+		}
+		observeRollout(cp, dc.Status.LatestVersion, dc.Status.ReadyReplicas)
+	}
+	cp.Status.Phase = phase
+	if len(unavailable) > 0 {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonScalingUp, strings.Join(unavailable, ", "))
+	} else {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionTrue, ReasonReplicasAvailable, "all replicas are available")
 	}
 	return nil
 }
 
-// Update status of component
-func (r *ReconcileComponent) UpdateStatus(cp *devconsoleapi.Component, status string) error {
-	if cp.Status.Phase != status {
-		cp.Status.Phase = status
-		err := r.client.Update(context.TODO(), cp)
-		if err != nil {
-			log.Error(err, "** failed to update component status **")
-			return err
+// ObserveRoute checks whether the route exposing the component has been admitted by a router.
+func (r *ReconcileComponent) ObserveRoute(cp *devconsoleapi.Component, route *routev1.Route) {
+	for _, ingress := range route.Status.Ingress {
+		for _, condition := range ingress.Conditions {
+			if condition.Type != routev1.RouteAdmitted {
+				continue
+			}
+			if condition.Status == corev1.ConditionTrue {
+				cp.Status.URL = routeURL(route, ingress)
+				setCondition(cp, ConditionRouteAdmitted, corev1.ConditionTrue, ReasonRouteAdmitted, fmt.Sprintf("Route %s admitted by router %s at %s", route.Name, ingress.RouterName, cp.Status.URL))
+				return
+			}
+			if condition.Reason == routerHostClaimedReason {
+				setCondition(cp, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonRouteHostConflict, condition.Message)
+				return
+			}
+			setCondition(cp, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonRouteRejected, condition.Message)
+			return
+		}
+	}
+	setCondition(cp, ConditionRouteAdmitted, corev1.ConditionUnknown, ReasonRouteAdmissionPending, fmt.Sprintf("Route %s is not admitted yet", route.Name))
+}
+
+// UpdateStatus updates the status of the component through the status subresource when it differs from the given
+// one. On conflict, the status is applied again on the latest version of the component.
+func (r *ReconcileComponent) UpdateStatus(cp *devconsoleapi.Component, status *devconsoleapi.ComponentStatus) error {
+	if equality.Semantic.DeepEqual(&cp.Status, status) {
+		return nil
+	}
+	newStatus := cp.Status.DeepCopy()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.client.Status().Update(context.TODO(), cp)
+		if errors.IsConflict(err) {
+			log.Info("** Conflict updating component status, retrying with the latest version **", "Component.Namespace", cp.Namespace, "Component.Name", cp.Name)
+			if getErr := r.client.Get(context.TODO(), types.NamespacedName{Name: cp.Name, Namespace: cp.Namespace}, cp); getErr != nil {
+				return getErr
+			}
+			cp.Status = *newStatus.DeepCopy()
 		}
+		return err
+	})
+	if err != nil {
+		log.Error(err, "** failed to update component status **")
+		return err
 	}
 	return nil
 }
 
-// GetGitSource return the GitSource associated to Component CR.
+// GetSourceSecret returns the Secret of the GitSource used to clone its repository, or nil when the GitSource does not
+// refer to any. A missing Secret sets the SourceResolved condition to false with a permanent error, the Component is
+// reconciled again once the Secret is created.
 func (r *ReconcileComponent) GetSourceSecret(cp *devconsoleapi.Component, gitSource *devconsoleapi.GitSource) (*corev1.Secret, error) {
 	// Check if secrets provided exist or not
 	if gitSource.Spec.SecretRef != nil && gitSource.Spec.SecretRef.Name != "" {
@@ -273,21 +659,68 @@ func (r *ReconcileComponent) GetSourceSecret(cp *devconsoleapi.Component, gitSou
 			return foundSecret, nil
 		}
 		if errors.IsNotFound(err) {
-			log.Info("** Secret NOT found ", "Secret.Namespace", foundSecret.Namespace, "Secret.Name", foundSecret.Name)
-			return nil, err
+			log.Info("** Secret NOT found ", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+			err := fmt.Errorf("secret %s of GitSource %s is not found", secret.Name, gitSource.Name)
+			setCondition(cp, ConditionSourceResolved, corev1.ConditionFalse, ReasonSourceSecretMissing, err.Error())
+			return nil, newPermanentError(err)
 		}
+		log.Error(err, "** failed to get the secret of the gitsource **")
 		return nil, err
 	}
 	return nil, nil
 }
 
+// newSecretMapper returns the Components to reconcile when a Secret changes, the ones referring to it:
+//   - securing their Route with it, so that a rotated certificate is copied to the Route
+//   - triggered by its webhook secret, so that the URL of the webhook is published once the secret is set
+//   - referencing it in their environment, so that they are rolled out
+//   - cloning their GitSource with it, so that a Component waiting for its source Secret is built once it is created
+func newSecretMapper(cl client.Client) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		cpList := &devconsoleapi.ComponentList{}
+		if err := cl.List(context.TODO(), &client.ListOptions{Namespace: obj.Meta.GetNamespace()}, cpList); err != nil {
+			log.Error(err, "** failed to list components using the secret **")
+			return nil
+		}
+		var requests []reconcile.Request
+		for i := range cpList.Items {
+			if !usesSecret(cl, &cpList.Items[i], obj.Meta.GetName()) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cpList.Items[i].Namespace, Name: cpList.Items[i].Name},
+			})
+		}
+		return requests
+	}
+}
+
+// usesSecret returns true when the Component refers to the Secret of the given name, through its TLS configuration,
+// its webhook, its environment or its GitSource.
+func usesSecret(cl client.Client, cp *devconsoleapi.Component, name string) bool {
+	if tlsSecretName(cp) == name || webhookSecretName(cp) == name {
+		return true
+	}
+	if _, secrets := envSources(cp); containsString(secrets, name) {
+		return true
+	}
+	if cp.Spec.GitSourceRef == "" {
+		return false
+	}
+	gitSource := &devconsoleapi.GitSource{}
+	if err := cl.Get(context.TODO(), types.NamespacedName{Namespace: cp.Namespace, Name: cp.Spec.GitSourceRef}, gitSource); err != nil {
+		return false
+	}
+	return gitSource.Spec.SecretRef != nil && gitSource.Spec.SecretRef.Name == name
+}
+
 // GetGitSource return the GitSource associated to Component CR.
 func (r *ReconcileComponent) GetGitSource(cp *devconsoleapi.Component) (*devconsoleapi.GitSource, error) {
 	// Validate if codebase is present since this is mandatory field
 	if cp.Spec.GitSourceRef == "" {
 		err := e.New("GitSource reference is not provided")
 		log.Error(err, "** failed to get gitsource **")
-		return nil, err
+		return nil, newPermanentError(err)
 	}
 	// Get gitsource referenced in component
 	gitSource := &devconsoleapi.GitSource{}
@@ -302,17 +735,24 @@ func (r *ReconcileComponent) GetGitSource(cp *devconsoleapi.Component) (*devcons
 	return gitSource, nil
 }
 
-// GetExposedPorts returns either the provided port in the component's spec or search for the builder image for exposed port.
-func (r *ReconcileComponent) GetExposedPorts(cr *devconsoleapi.Component, imageTag string, is *imagev1.ImageStream) ([]corev1.ContainerPort, error) {
+// GetExposedPorts returns either the provided port in the component's spec, the default ports of the builder image
+// catalog or search for the builder image for exposed ports. The ports are named after their number and protocol.
+func (r *ReconcileComponent) GetExposedPorts(cr *devconsoleapi.Component, imageTag string, is *imagev1.ImageStream, builder *BuilderImage) ([]corev1.ContainerPort, error) {
 	if cr.Spec.Port != 0 { // port in component's spec overrides exposed port
 		containerPorts := []corev1.ContainerPort{{
 			ContainerPort: cr.Spec.Port,
 			Protocol:      corev1.ProtocolTCP,
 		}}
-		return containerPorts, nil
+		return namedPorts(containerPorts), nil
+	}
+	if builder != nil && len(builder.Ports) > 0 {
+		return namedPorts(builder.containerPorts()), nil
 	}
-	// otherwise extract port from builder docker image.
-	isi, err := r.GetBuilderImageStreamImage("latest", is)
+	if is == nil { // no builder image to inspect, the default port is used
+		return namedPorts(nil), nil
+	}
+	// otherwise extract ports from builder docker image.
+	isi, err := r.GetBuilderImageStreamImage(imageTag, is)
 	if err != nil {
 		return nil, err
 	}
@@ -320,7 +760,7 @@ func (r *ReconcileComponent) GetExposedPorts(cr *devconsoleapi.Component, imageT
 	if err != nil {
 		return nil, err
 	}
-	return ports, nil
+	return namedPorts(ports), nil
 }
 
 // GetBuilderImageStreamImage retrieves exposed port from builder's imagestreamimage.
@@ -343,9 +783,64 @@ func (r *ReconcileComponent) GetBuilderImageStreamImage(imageTag string, is *ima
 	return nil, fmt.Errorf("unable to find tag %s for image %s", imageTag, is.Name)
 }
 
-// CreateRoute creates a route to expose the service if CRD's exposed field is true.
-func (r *ReconcileComponent) CreateRoute(cp *devconsoleapi.Component) (*routev1.Route, error) {
-	route := newRoute(cp)
+// hasImageStreamTag returns true when the image stream provides the tag, either already imported in its status or
+// being imported from its spec.
+func hasImageStreamTag(is *imagev1.ImageStream, tag string) bool {
+	for _, t := range is.Status.Tags {
+		if t.Tag == tag {
+			return true
+		}
+	}
+	for _, t := range is.Spec.Tags {
+		if t.Name == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// isImageStreamTagImported returns true when an image of the tag has been imported in the status of the image stream.
+func isImageStreamTagImported(is *imagev1.ImageStream, tag string) bool {
+	for _, t := range is.Status.Tags {
+		if t.Tag == tag && len(t.Items) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// imageStreamTagImportFailure returns the message of the failed import of the tag of the image stream, or an empty
+// string when its import did not fail.
+func imageStreamTagImportFailure(is *imagev1.ImageStream, tag string) string {
+	for _, t := range is.Status.Tags {
+		if t.Tag != tag {
+			continue
+		}
+		for _, condition := range t.Conditions {
+			if condition.Type == imagev1.ImportSuccess && condition.Status == corev1.ConditionFalse {
+				return condition.Message
+			}
+		}
+	}
+	return ""
+}
+
+// imageStreamTags returns the sorted names of the tags imported in the status of the image stream.
+func imageStreamTags(is *imagev1.ImageStream) []string {
+	var tags []string
+	for _, tag := range is.Status.Tags {
+		if len(tag.Items) > 0 {
+			tags = append(tags, tag.Tag)
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// CreateRoute creates a route to expose the primary port of the service if CRD's exposed field is true, or updates the
+// existing one when it drifted from the component's spec.
+func (r *ReconcileComponent) CreateRoute(cp *devconsoleapi.Component, port *corev1.ContainerPort, tls *routev1.TLSConfig) (*routev1.Route, error) {
+	route := newRoute(cp, port, tls)
 	if err := controllerutil.SetControllerReference(cp, route, r.scheme); err != nil {
 		log.Error(err, "** Setting owner reference fails **")
 		return nil, err
@@ -353,7 +848,15 @@ func (r *ReconcileComponent) CreateRoute(cp *devconsoleapi.Component) (*routev1.
 	foundRoute := &routev1.Route{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{Name: route.Name, Namespace: route.Namespace}, foundRoute)
 	if err == nil {
-		log.Info("** Skip Creating Route: Already exist", "Route.Namespace", foundRoute.Namespace, "Route.Name", foundRoute.Name)
+		if !updateRoute(foundRoute, route) {
+			log.Info("** Skip Updating Route: Already up to date", "Route.Namespace", foundRoute.Namespace, "Route.Name", foundRoute.Name)
+			return foundRoute, nil
+		}
+		log.Info("💡💡  Updating Route  💡💡", "Route.Namespace", foundRoute.Namespace, "Route.Name", foundRoute.Name)
+		if err := r.client.Update(context.TODO(), foundRoute); err != nil {
+			log.Error(err, "** Route update fails **")
+			return nil, err
+		}
 		return foundRoute, nil
 	}
 	if errors.IsNotFound(err) {
@@ -368,13 +871,13 @@ func (r *ReconcileComponent) CreateRoute(cp *devconsoleapi.Component) (*routev1.
 	return nil, err
 }
 
-// CreateService creates a service resource to expose the component S2I deployed image.
+// CreateService creates or updates a service resource exposing all the ports of the component S2I deployed image.
 func (r *ReconcileComponent) CreateService(cp *devconsoleapi.Component, containerPorts []corev1.ContainerPort) (*corev1.Service, error) {
-	var port = containerPorts[0].ContainerPort
-	svc, err := newService(cp, port)
+	svc, err := newService(cp, containerPorts)
 	if err != nil {
 		log.Info("** CreateService: Port is not valid")
-		return nil, err
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonPortInvalid, err.Error())
+		return nil, newPermanentError(err)
 	}
 	if err := controllerutil.SetControllerReference(cp, svc, r.scheme); err != nil {
 		log.Error(err, "** Setting owner reference fails **")
@@ -383,7 +886,15 @@ func (r *ReconcileComponent) CreateService(cp *devconsoleapi.Component, containe
 	foundSvc := &corev1.Service{}
 	err = r.client.Get(context.TODO(), types.NamespacedName{Name: svc.Name, Namespace: svc.Namespace}, foundSvc)
 	if err == nil {
-		log.Info("** Skip Creating Service: Already exist", "Service.Namespace", foundSvc.Namespace, "Service.Name", foundSvc.Name)
+		if !updateService(foundSvc, svc) {
+			log.Info("** Skip Updating Service: Already up to date", "Service.Namespace", foundSvc.Namespace, "Service.Name", foundSvc.Name)
+			return foundSvc, nil
+		}
+		log.Info("💡💡  Updating Service 💡💡", "Service.Namespace", foundSvc.Namespace, "Service.Name", foundSvc.Name)
+		if err := r.client.Update(context.TODO(), foundSvc); err != nil {
+			log.Error(err, "** Service update fails **")
+			return nil, err
+		}
 		return foundSvc, nil
 	}
 	if errors.IsNotFound(err) {
@@ -398,9 +909,13 @@ func (r *ReconcileComponent) CreateService(cp *devconsoleapi.Component, containe
 	return nil, err
 }
 
-// CreateDeploymentConfig creates a DeploymentConfig OpenShift resource used in S2I.
-func (r *ReconcileComponent) CreateDeploymentConfig(cp *devconsoleapi.Component, outputIS *imagev1.ImageStream, containerPorts []corev1.ContainerPort) (*v1.DeploymentConfig, error) {
-	dc := newDeploymentConfig(cp, outputIS, containerPorts)
+// CreateDeploymentConfig creates or updates a DeploymentConfig OpenShift resource used in S2I.
+// The pod spec of a previous version replaces the desired one while the component is rolled back.
+func (r *ReconcileComponent) CreateDeploymentConfig(cp *devconsoleapi.Component, outputIS *imagev1.ImageStream, containerPorts []corev1.ContainerPort, builder *BuilderImage, configHash string, rollback *corev1.PodSpec) (*v1.DeploymentConfig, error) {
+	dc := newDeploymentConfig(cp, outputIS, containerPorts, builder, configHash)
+	if rollback != nil {
+		rollBackDeploymentConfig(dc, rollback)
+	}
 	if err := controllerutil.SetControllerReference(cp, dc, r.scheme); err != nil {
 		log.Error(err, "** Setting owner reference fails **")
 		return nil, err
@@ -408,7 +923,23 @@ func (r *ReconcileComponent) CreateDeploymentConfig(cp *devconsoleapi.Component,
 	foundDc := &v1.DeploymentConfig{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{Name: dc.Name, Namespace: dc.Namespace}, foundDc)
 	if err == nil {
-		log.Info("** Skip Creating DeploymentConfig: Already exist", "DeploymentConfig.Namespace", foundDc.Namespace, "DeploymentConfig.Name", foundDc.Name)
+		// the replicas of an autoscaled component are set by its autoscaler
+		if isAutoscaled(cp) {
+			dc.Spec.Replicas = foundDc.Spec.Replicas
+		}
+		updated := updateDeploymentConfig(foundDc, dc)
+		if rollback != nil && rollBackImages(&foundDc.Spec.Template.Spec, rollback) {
+			updated = true
+		}
+		if !updated {
+			log.Info("** Skip Updating DeploymentConfig: Already up to date", "DeploymentConfig.Namespace", foundDc.Namespace, "DeploymentConfig.Name", foundDc.Name)
+			return foundDc, nil
+		}
+		log.Info("💡💡  Updating DeploymentConfig 💡💡", "DeploymentConfig.Namespace", foundDc.Namespace, "DeploymentConfig.Name", foundDc.Name)
+		if err := r.client.Update(context.TODO(), foundDc); err != nil {
+			log.Error(err, "** DeploymentConfig update fails **")
+			return nil, err
+		}
 		return foundDc, nil
 	}
 	if errors.IsNotFound(err) {
@@ -423,7 +954,7 @@ func (r *ReconcileComponent) CreateDeploymentConfig(cp *devconsoleapi.Component,
 	return nil, err
 }
 
-// CreateBuildConfig creates a BuildConfig OpenShift resource used in S2I.
+// CreateBuildConfig creates or updates a BuildConfig OpenShift resource used in S2I.
 func (r *ReconcileComponent) CreateBuildConfig(cr *devconsoleapi.Component, builderIS *imagev1.ImageStream, gitSource *devconsoleapi.GitSource, secret *corev1.Secret) (*buildv1.BuildConfig, error) {
 	bc := newBuildConfig(cr, builderIS, gitSource, secret)
 	if err := controllerutil.SetControllerReference(cr, bc, r.scheme); err != nil {
@@ -433,7 +964,15 @@ func (r *ReconcileComponent) CreateBuildConfig(cr *devconsoleapi.Component, buil
 	foundBc := &buildv1.BuildConfig{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{Name: bc.Name, Namespace: bc.Namespace}, foundBc)
 	if err == nil {
-		log.Info("** Skip Creating BuildConfig: Already exist", "BuildConfig.Namespace", foundBc.Namespace, "BuildConfig.Name", foundBc.Name)
+		if !updateBuildConfig(foundBc, bc) {
+			log.Info("** Skip Updating BuildConfig: Already up to date", "BuildConfig.Namespace", foundBc.Namespace, "BuildConfig.Name", foundBc.Name)
+			return foundBc, nil
+		}
+		log.Info("💡💡 Updating BuildConfig 💡💡", "BuildConfig.Namespace", foundBc.Namespace, "BuildConfig.Name", foundBc.Name)
+		if err := r.client.Update(context.TODO(), foundBc); err != nil {
+			log.Error(err, "** BuildConfig update fails **")
+			return nil, err
+		}
 		return foundBc, nil
 	}
 	if errors.IsNotFound(err) {
@@ -448,7 +987,8 @@ func (r *ReconcileComponent) CreateBuildConfig(cr *devconsoleapi.Component, buil
 	return nil, err
 }
 
-// CreateOutputImageStream creates an empty image name that holds the source code of the component to build and deploy.
+// CreateOutputImageStream creates an empty image name that holds the source code of the component to build and deploy,
+// or the image stream importing the external image of an image-only component.
 func (r *ReconcileComponent) CreateOutputImageStream(cp *devconsoleapi.Component) (*imagev1.ImageStream, error) {
 	outputIS := newOutputImageStream(cp)
 	if err := controllerutil.SetControllerReference(cp, outputIS, r.scheme); err != nil {
@@ -459,7 +999,17 @@ func (r *ReconcileComponent) CreateOutputImageStream(cp *devconsoleapi.Component
 	foundOutputIS := &imagev1.ImageStream{}
 	err := r.client.Get(context.TODO(), types.NamespacedName{Name: outputIS.Name, Namespace: outputIS.Namespace}, foundOutputIS)
 	if err == nil {
-		log.Info("** Skip Creating output ImageStream: Already exist", "ImageStream.Namespace", foundOutputIS.Namespace, "ImageStream.Name", foundOutputIS.Name)
+		metaUpdated := updateObjectMeta(&foundOutputIS.ObjectMeta, &outputIS.ObjectMeta)
+		tagsUpdated := updateImageStreamTags(foundOutputIS, outputIS)
+		if !metaUpdated && !tagsUpdated {
+			log.Info("** Skip Updating output ImageStream: Already up to date", "ImageStream.Namespace", foundOutputIS.Namespace, "ImageStream.Name", foundOutputIS.Name)
+			return foundOutputIS, nil
+		}
+		log.Info("💡💡  Updating output ImageStream 💡💡", "ImageStream.Namespace", foundOutputIS.Namespace, "ImageStream.Name", foundOutputIS.Name)
+		if err := r.client.Update(context.TODO(), foundOutputIS); err != nil {
+			log.Error(err, "** output ImageStream update fails **")
+			return nil, err
+		}
 		return foundOutputIS, nil
 	}
 	if errors.IsNotFound(err) {
@@ -474,42 +1024,69 @@ func (r *ReconcileComponent) CreateOutputImageStream(cp *devconsoleapi.Component
 	return nil, err
 }
 
-// CreateBuilderImageStream either creates an builder image stream fetch from Docker hub or reuse an existing
-// image stream in OpenShift namespace.
-func (r *ReconcileComponent) CreateBuilderImageStream(cp *devconsoleapi.Component) (*imagev1.ImageStream, error) {
+// CreateBuilderImageStream either creates an builder image stream fetch from Docker hub, as described by the builder
+// image catalog, or reuse an existing image stream in OpenShift namespace. A build type found in neither of them is a
+// permanent error, the Component is reconciled again once the builder image catalog changes.
+func (r *ReconcileComponent) CreateBuilderImageStream(cp *devconsoleapi.Component, builder *BuilderImage) (*imagev1.ImageStream, error) {
 	var newImageForBuilder *imagev1.ImageStream
 	found := &imagev1.ImageStream{}
-	err := r.client.Get(context.TODO(), types.NamespacedName{Name: cp.Spec.BuildType, Namespace: openshiftNamespace}, found)
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: builderName(cp), Namespace: openshiftNamespace}, found)
 	if err == nil {
 		log.Info("** Skip Creating builder ImageStream: an OpenShift image already exist", "ImageStream.Namespace", found.Namespace, "ImageStream.Name", found.Name)
 		return found, nil
 	}
 	if errors.IsNotFound(err) { // OpenShift builder image is not present, fallback to create one.
-		log.Info(fmt.Sprintf("** Searching in namespace %s imagestream %s fails **", openshiftNamespace, cp.Spec.BuildType))
-		newImageForBuilder = newImageStreamFromDocker(cp)
-		if newImageForBuilder == nil {
+		log.Info(fmt.Sprintf("** Searching in namespace %s imagestream %s fails **", openshiftNamespace, builderName(cp)))
+		if builder == nil {
 			log.Error(err, "** Creating new BUILDER image fails **")
-			return nil, errors.NewNotFound(schema.GroupResource{Resource: "ImageStream"}, "builder image for build not found")
+			return nil, newPermanentError(fmt.Errorf("builder image %s is found neither in namespace %s nor in the builder image catalog", builderName(cp), openshiftNamespace))
 		}
+		newImageForBuilder = newImageStreamFromDocker(cp, builder)
 		foundBuilderIS := &imagev1.ImageStream{}
 		err := r.client.Get(context.TODO(), types.NamespacedName{Name: newImageForBuilder.Name, Namespace: newImageForBuilder.Namespace}, foundBuilderIS)
 		if err == nil {
-			log.Info("** Skip Creating builder ImageStream: Already exist", "ImageStream.Namespace", foundBuilderIS.Namespace, "ImageStream.Name", foundBuilderIS.Name)
+			// the builder image stream may be shared by several components, each one of them owns it
+			ownerAdded := addOwnerReference(cp, foundBuilderIS)
+			tagsUpdated := updateImageStreamTags(foundBuilderIS, newImageForBuilder)
+			if !ownerAdded && !tagsUpdated {
+				log.Info("** Skip Creating builder ImageStream: Already exist", "ImageStream.Namespace", foundBuilderIS.Namespace, "ImageStream.Name", foundBuilderIS.Name)
+				return foundBuilderIS, nil
+			}
+			log.Info("** Updating existing builder ImageStream", "ImageStream.Namespace", foundBuilderIS.Namespace, "ImageStream.Name", foundBuilderIS.Name)
+			if err := r.client.Update(context.TODO(), foundBuilderIS); err != nil {
+				log.Error(err, "** builder ImageStream update fails **")
+				return nil, err
+			}
 			return foundBuilderIS, nil
 		}
 		if errors.IsNotFound(err) {
+			addOwnerReference(cp, newImageForBuilder)
 			log.Info("** 💡💡 Creating a new builder ImageStream 💡💡", "ImageStream.Namespace", newImageForBuilder.Namespace, "ImageStream.Name", newImageForBuilder.Name)
 			err := r.client.Create(context.TODO(), newImageForBuilder)
 			if err != nil && !errors.IsAlreadyExists(err) {
 				log.Error(err, "** builder ImageStream creation fails **")
 				return nil, err
 			}
-			if err := controllerutil.SetControllerReference(cp, newImageForBuilder, r.scheme); err != nil {
-				log.Error(err, "** Setting owner reference fails **")
-				return nil, err
-			}
-			return foundBuilderIS, nil
+			return newImageForBuilder, nil
 		}
 	}
-	return newImageForBuilder, nil
+	return nil, err
+}
+
+// addOwnerReference adds a non controlling owner reference to the Component on the given object, so that an object
+// shared by several Components is only garbage collected once all of them are deleted. It returns false when the
+// reference was already present.
+func addOwnerReference(cp *devconsoleapi.Component, object metav1.Object) bool {
+	if isOwnedBy(object, cp) {
+		return false
+	}
+	blockOwnerDeletion := true
+	object.SetOwnerReferences(append(object.GetOwnerReferences(), metav1.OwnerReference{
+		APIVersion:         devconsoleapi.SchemeGroupVersion.String(),
+		Kind:               "Component",
+		Name:               cp.Name,
+		UID:                cp.UID,
+		BlockOwnerDeletion: &blockOwnerDeletion,
+	}))
+	return true
 }
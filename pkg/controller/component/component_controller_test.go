@@ -14,22 +14,31 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	k8sappsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
 	corev1 "k8s.io/api/core/v1"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"fmt"
 
 	dockerapiv10 "github.com/openshift/api/image/docker10"
+	fakebuild "github.com/openshift/client-go/build/clientset/versioned/fake"
 	fakeimage "github.com/openshift/client-go/image/clientset/versioned/fake"
 )
 
@@ -93,8 +102,9 @@ func TestComponentController(t *testing.T) {
 	}
 	// Register operator types with the runtime scheme.
 	s := scheme.Scheme
-	s.AddKnownTypes(devconsoleapi.SchemeGroupVersion, cp)
+	s.AddKnownTypes(devconsoleapi.SchemeGroupVersion, cp, &devconsoleapi.ComponentList{})
 	s.AddKnownTypes(devconsoleapi.SchemeGroupVersion, gs)
+	s.AddKnownTypes(devconsoleapi.SchemeGroupVersion, &devconsoleapi.GitSourceAnalysis{}, &devconsoleapi.GitSourceAnalysisList{})
 	s.AddKnownTypes(corev1.SchemeGroupVersion, secret)
 
 	// register openshift resource specific schema
@@ -154,7 +164,7 @@ func TestComponentController(t *testing.T) {
 		require.NoError(t, errGetBuilderImage, "builder imagestream is not created")
 		require.Equal(t, cp.Spec.BuildType, isBuilder.ObjectMeta.Name, "imagestream builder should be named after component's buildtype")
 		require.Equal(t, Namespace, isBuilder.ObjectMeta.Namespace, "")
-		require.Equal(t, 7, len(isBuilder.Labels), "imagestream builder should contain seven labels")
+		require.Equal(t, 8, len(isBuilder.Labels), "imagestream builder should contain eight labels")
 		require.Equal(t, Name, isBuilder.Labels["app"], "imagestream builder should have a label with app of CR")
 		require.Equal(t, "application-1", isBuilder.Labels["app.kubernetes.io/part-of"], "isBuilder builder should have a label for part-of of CR")
 		require.Equal(t, "MyComp", isBuilder.Labels["app.kubernetes.io/name"], "isBuilder builder should have a label with name of CR")
@@ -173,10 +183,11 @@ func TestComponentController(t *testing.T) {
 		errGetBC := cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, bc)
 		require.NoError(t, errGetBC, "build config is not created")
 		require.Equal(t, "https://somegit.con/myrepo", bc.Spec.Source.Git.URI, "build config should not have any source attached")
-		require.Equal(t, 2, len(bc.Spec.Triggers), "build config contains 2 triggers")
+		require.Equal(t, 3, len(bc.Spec.Triggers), "build config contains 3 triggers")
 		require.Equal(t, buildv1.ConfigChangeBuildTriggerType, bc.Spec.Triggers[0].Type, "build config should be triggered on config change")
-		require.Equal(t, buildv1.ImageChangeBuildTriggerType, bc.Spec.Triggers[1].Type, "build config should be triggered on image change")
-		require.Equal(t, 7, len(bc.Labels), "bc should contain seven labels")
+		require.Equal(t, buildv1.GenericWebHookBuildTriggerType, bc.Spec.Triggers[1].Type, "build config should be triggered by a generic webhook")
+		require.Equal(t, buildv1.ImageChangeBuildTriggerType, bc.Spec.Triggers[2].Type, "build config should be triggered on image change")
+		require.Equal(t, 8, len(bc.Labels), "bc should contain eight labels")
 		require.Equal(t, Name, bc.ObjectMeta.Labels["app"], "bc builder should have a label with app of CR")
 		require.Equal(t, "application-1", bc.ObjectMeta.Labels["app.kubernetes.io/part-of"], "bc builder should have a label with part-of of CR")
 		require.Equal(t, "MyComp", bc.ObjectMeta.Labels["app.kubernetes.io/name"], "bc builder should have a label with name of CR")
@@ -194,7 +205,7 @@ func TestComponentController(t *testing.T) {
 		require.Equal(t, appsv1.DeploymentTriggerOnConfigChange, dc.Spec.Triggers[0].Type, "deployment config should be triggered by DeploymentTriggerOnConfigChange")
 		require.Equal(t, appsv1.DeploymentTriggerOnImageChange, dc.Spec.Triggers[1].Type, "deployment config should be triggered by DeploymentTriggerOnImageChange")
 		require.Equal(t, Name+":latest", dc.Spec.Triggers[1].ImageChangeParams.From.Name, "deployment config should be triggered by DeploymentTriggerOnImageChange from bc-output")
-		require.Equal(t, 7, len(dc.Labels), "dc should contain seven labels")
+		require.Equal(t, 8, len(dc.Labels), "dc should contain eight labels")
 		require.Equal(t, Name, dc.ObjectMeta.Labels["app"], "dc should have a label with app of CR")
 		require.Equal(t, "application-1", dc.ObjectMeta.Labels["app.kubernetes.io/part-of"], "dc builder should have a label with part-of of CR")
 		require.Equal(t, "MyComp", dc.ObjectMeta.Labels["app.kubernetes.io/name"], "dc builder should have a label with name of CR")
@@ -267,7 +278,8 @@ func TestComponentController(t *testing.T) {
 		rte := &routev1.Route{}
 		errGetRte := cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, rte)
 		require.NoError(t, errGetRte, "route is not created")
-		require.Equal(t, intstr.IntOrString{IntVal: Port}, rte.Spec.Port.TargetPort)
+		require.Equal(t, intstr.FromString("3000-tcp"), rte.Spec.Port.TargetPort)
+		require.Equal(t, "3000-tcp", svc.Spec.Ports[0].Name, "route should target the service port by name")
 
 		// TODO(corinne): ask Dipak
 		//ep := &corev1.Endpoints{}
@@ -278,6 +290,276 @@ func TestComponentController(t *testing.T) {
 		//require.NoError(t, errGetEp, "endpoints are not created")
 	})
 
+	t.Run("with ReconcileComponent CR securing its route with a TLS secret", func(t *testing.T) {
+		//given
+		cpTLS := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         Port,
+				Exposed:      true,
+				TLS: &devconsoleapi.ComponentTLS{
+					Termination:                   "edge",
+					InsecureEdgeTerminationPolicy: "Redirect",
+					SecretRef:                     &corev1.LocalObjectReference{Name: "my-tls"},
+				},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpTLS)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: Name, Namespace: Namespace}}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonTLSSecretNotFound)
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, &routev1.Route{})), "route should wait for the tls secret")
+
+		//given
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-tls", Namespace: Namespace},
+			Type:       corev1.SecretTypeTLS,
+			Data: map[string][]byte{
+				corev1.TLSCertKey:       []byte("cert-1"),
+				corev1.TLSPrivateKeyKey: []byte("key-1"),
+			},
+		}
+		require.NoError(t, cl.Create(context.Background(), secret))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		rte := &routev1.Route{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, rte), "route is not created")
+		require.NotNil(t, rte.Spec.TLS, "route should be secured")
+		require.Equal(t, routev1.TLSTerminationEdge, rte.Spec.TLS.Termination)
+		require.Equal(t, routev1.InsecureEdgeTerminationPolicyRedirect, rte.Spec.TLS.InsecureEdgeTerminationPolicy)
+		require.Equal(t, "cert-1", rte.Spec.TLS.Certificate)
+		require.Equal(t, "key-1", rte.Spec.TLS.Key)
+
+		//given the secret is rotated
+		secret.Data[corev1.TLSCertKey] = []byte("cert-2")
+		secret.Data[corev1.TLSPrivateKeyKey] = []byte("key-2")
+		require.NoError(t, cl.Update(context.Background(), secret))
+		requests := newSecretMapper(cl)(handler.MapObject{Meta: secret, Object: secret})
+		require.Equal(t, []reconcile.Request{req}, requests, "component should be reconciled when its tls secret changes")
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		rte = &routev1.Route{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, rte))
+		require.Equal(t, "cert-2", rte.Spec.TLS.Certificate, "route certificate should be rotated")
+		require.Equal(t, "key-2", rte.Spec.TLS.Key, "route key should be rotated")
+	})
+
+	t.Run("with ReconcileComponent CR routed on a custom host and path", func(t *testing.T) {
+		//given
+		cpHost := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         Port,
+				Exposed:      true,
+				Host:         "www.example.com",
+				Path:         "/api",
+			},
+		}
+		otherRoute := &routev1.Route{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: Namespace},
+			Spec: routev1.RouteSpec{
+				Host: "www.example.com",
+				Path: "/api",
+				To:   routev1.RouteTargetReference{Kind: "Service", Name: "other"},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpHost, otherRoute)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: Name, Namespace: Namespace}}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonRouteHostConflict)
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, &routev1.Route{})), "conflicting route should not be created")
+
+		//given the other route moves to another path
+		otherRoute.Spec.Path = "/web"
+		require.NoError(t, cl.Update(context.Background(), otherRoute))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		rte := &routev1.Route{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, rte), "route is not created")
+		require.Equal(t, "www.example.com", rte.Spec.Host)
+		require.Equal(t, "/api", rte.Spec.Path)
+
+		//given the route is admitted
+		rte.Status.Ingress = []routev1.RouteIngress{{
+			Host:       "www.example.com",
+			RouterName: "default",
+			Conditions: []routev1.RouteIngressCondition{{Type: routev1.RouteAdmitted, Status: corev1.ConditionTrue}},
+		}}
+		require.NoError(t, cl.Update(context.Background(), rte))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionRouteAdmitted, corev1.ConditionTrue, ReasonRouteAdmitted)
+		require.Equal(t, "http://www.example.com/api", instance.Status.URL, "admitted url should be reported")
+	})
+
+	t.Run("with ReconcileComponent CR using a wildcard policy without host", func(t *testing.T) {
+		//given
+		cpWildcard := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:      "nodejs",
+				GitSourceRef:   "my-git-source",
+				Port:           Port,
+				Exposed:        true,
+				WildcardPolicy: "Subdomain",
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpWildcard)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: Name, Namespace: Namespace}}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonRouteConfigInvalid)
+	})
+
+	t.Run("with ReconcileComponent CR injecting environment from a ConfigMap and a Secret", func(t *testing.T) {
+		//given
+		cpEnv := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         Port,
+				Env: []corev1.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "DB_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "db"}, Key: "password"},
+					}},
+				},
+				EnvFrom:  []corev1.EnvFromSource{{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}}},
+				BuildEnv: []corev1.EnvVar{{Name: "NPM_MIRROR", Value: "https://npm.corp"}},
+			},
+		}
+		settings := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: Namespace},
+			Data:       map[string]string{"FEATURE": "on"},
+		}
+		db := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: Namespace},
+			Data:       map[string][]byte{"password": []byte("s3cr3t")},
+		}
+		cl := fake.NewFakeClient(gs, cpEnv, settings, db)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: Name, Namespace: Namespace}}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc), "deployment config is not created")
+		require.Equal(t, cpEnv.Spec.Env, dc.Spec.Template.Spec.Containers[0].Env)
+		require.Equal(t, cpEnv.Spec.EnvFrom, dc.Spec.Template.Spec.Containers[0].EnvFrom)
+		hash := dc.Spec.Template.Annotations[configHashAnnotation]
+		require.NotEmpty(t, hash, "pod template should have the hash of the configuration")
+		bc := &buildv1.BuildConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, bc), "build config is not created")
+		require.Equal(t, cpEnv.Spec.BuildEnv, bc.Spec.Strategy.SourceStrategy.Env, "build config should have the build environment")
+
+		//given the ConfigMap changes
+		settings.Data["FEATURE"] = "off"
+		require.NoError(t, cl.Update(context.Background(), settings))
+		requests := newEnvSourceMapper(cl)(handler.MapObject{Meta: settings, Object: settings})
+		require.Equal(t, []reconcile.Request{req}, requests, "component should be reconciled when its configmap changes")
+		require.Empty(t, newSecretMapper(cl)(handler.MapObject{Meta: &metav1.ObjectMeta{Name: "settings", Namespace: Namespace}, Object: &corev1.Secret{}}),
+			"component should not be reconciled for a secret named after its configmap")
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		dc = &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.NotEqual(t, hash, dc.Spec.Template.Annotations[configHashAnnotation], "pod template hash should change to roll the component out")
+	})
+
+	t.Run("with ReconcileComponent CR using an unsupported TLS termination", func(t *testing.T) {
+		//given
+		cpTLS := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         Port,
+				Exposed:      true,
+				TLS:          &devconsoleapi.ComponentTLS{Termination: "passthrough", InsecureEdgeTerminationPolicy: "Allow"},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpTLS)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: Name, Namespace: Namespace}}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonTLSConfigInvalid)
+	})
+
 	t.Run("with ReconcileComponent CR containing all optional fields for service port and route should create resources", func(t *testing.T) {
 		//given
 		// Objects to track in the fake client.
@@ -324,6 +606,11 @@ func TestComponentController(t *testing.T) {
 				Namespace: "openshift",
 			},
 			Spec: imagev1.ImageStreamSpec{},
+			Status: imagev1.ImageStreamStatus{
+				Tags: []imagev1.NamedTagEventList{{
+					Tag: "latest",
+				}},
+			},
 		}
 		// Objects to track in the fake client.
 		objs := []runtime.Object{
@@ -366,12 +653,13 @@ func TestComponentController(t *testing.T) {
 		errGetBC := cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, bc)
 		require.NoError(t, errGetBC, "build config is not created")
 		require.Equal(t, "https://somegit.con/myrepo", bc.Spec.Source.Git.URI, "build config should not have any source attached")
-		require.Equal(t, 2, len(bc.Spec.Triggers), "build config contains 2 triggers")
+		require.Equal(t, 3, len(bc.Spec.Triggers), "build config contains 3 triggers")
 		require.Equal(t, buildv1.ConfigChangeBuildTriggerType, bc.Spec.Triggers[0].Type, "build config should be triggered on config change")
-		require.Equal(t, buildv1.ImageChangeBuildTriggerType, bc.Spec.Triggers[1].Type, "build config should be triggered on image change")
+		require.Equal(t, buildv1.GenericWebHookBuildTriggerType, bc.Spec.Triggers[1].Type, "build config should be triggered by a generic webhook")
+		require.Equal(t, buildv1.ImageChangeBuildTriggerType, bc.Spec.Triggers[2].Type, "build config should be triggered on image change")
 		require.Equal(t, "openshift", bc.Spec.CommonSpec.Strategy.SourceStrategy.From.Namespace, "builder image used in build config should be taken from openshift namespace")
 		require.Equal(t, "nodejs:latest", bc.Spec.CommonSpec.Strategy.SourceStrategy.From.Name, "builder image used in build config should be taken from openshift's nodejs image")
-		require.Equal(t, 7, len(bc.Labels), "bc should contain seven labels")
+		require.Equal(t, 8, len(bc.Labels), "bc should contain eight labels")
 		require.Equal(t, Name, bc.ObjectMeta.Labels["app"], "bc builder should have a label with app of CR")
 		require.Equal(t, "application-1", bc.ObjectMeta.Labels["app.kubernetes.io/part-of"], "bc builder should have a label with part-of of CR")
 		require.Equal(t, "MyComp", bc.ObjectMeta.Labels["app.kubernetes.io/name"], "bc builder should have a label with name of CR")
@@ -389,7 +677,7 @@ func TestComponentController(t *testing.T) {
 		require.Equal(t, appsv1.DeploymentTriggerOnConfigChange, dc.Spec.Triggers[0].Type, "deployment config should be triggered by DeploymentTriggerOnConfigChange")
 		require.Equal(t, appsv1.DeploymentTriggerOnImageChange, dc.Spec.Triggers[1].Type, "deployment config should be triggered by DeploymentTriggerOnImageChange")
 		require.Equal(t, Name+":latest", dc.Spec.Triggers[1].ImageChangeParams.From.Name, "deployment config should be triggered by DeploymentTriggerOnImageChange from bc-output")
-		require.Equal(t, 7, len(dc.Labels), "dc should contain seven labels")
+		require.Equal(t, 8, len(dc.Labels), "dc should contain eight labels")
 		require.Equal(t, Name, dc.ObjectMeta.Labels["app"], "dc should have a label with app of CR")
 		require.Equal(t, "application-1", dc.ObjectMeta.Labels["app.kubernetes.io/part-of"], "dc builder should have a label with part-of of CR")
 		require.Equal(t, "MyComp", dc.ObjectMeta.Labels["app.kubernetes.io/name"], "dc builder should have a label with name of CR")
@@ -456,6 +744,32 @@ func TestComponentController(t *testing.T) {
 		require.Equal(t, "my-secret", bc.Spec.CommonSpec.Source.SourceSecret.Name, "Secret name is not present")
 	})
 
+	t.Run("with missing secret defined in the GitSource", func(t *testing.T) {
+		//given
+		cl := fake.NewFakeClient(gs, cp)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile should not be requeued until the secret is created")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionSourceResolved, corev1.ConditionFalse, ReasonSourceSecretMissing)
+		requireCondition(t, instance, ConditionReady, corev1.ConditionFalse, ReasonSourceSecretMissing)
+		require.Contains(t, getCondition(instance, ConditionSourceResolved).Message, "my-secret")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &buildv1.BuildConfig{}), "build config should not be created without its secret")
+		requests := newSecretMapper(cl)(handler.MapObject{Meta: secret, Object: secret})
+		require.Equal(t, []reconcile.Request{req}, requests, "creating the secret should reconcile the component")
+	})
+
 	t.Run("without secret defined in the GitSource", func(t *testing.T) {
 		// Add Secret reference in GitSource
 		gs.Spec.SecretRef = nil
@@ -525,11 +839,18 @@ func TestComponentController(t *testing.T) {
 		_, err := r.Reconcile(req)
 
 		//then
-		require.Error(t, err, "reconcile is failing")
+		require.NoError(t, err, "reconcile should wait for the build type detection")
 
 		instance := &devconsoleapi.Component{}
 		errGet := r.client.Get(context.TODO(), req.NamespacedName, instance)
 		require.NoError(t, errGet, "component is not created")
+		requireCondition(t, instance, ConditionBuildTypeResolved, corev1.ConditionUnknown, ReasonAnalysisPending)
+		requireCondition(t, instance, ConditionReady, corev1.ConditionFalse, ReasonAnalysisPending)
+
+		gsa := &devconsoleapi.GitSourceAnalysis{}
+		errGetGSA := cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, gsa)
+		require.NoError(t, errGetGSA, "gitsourceanalysis is not created")
+		require.Equal(t, "my-git-source", gsa.Spec.GitSourceRef.Name, "gitsourceanalysis should analyze the component's gitsource")
 
 		is := &imagev1.ImageStream{}
 		errGetImage := cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, is)
@@ -545,6 +866,81 @@ func TestComponentController(t *testing.T) {
 		require.Error(t, errGetDC, "deployment config should not be created")
 	})
 
+	t.Run("with ReconcileComponent CR with auto buildtype and a done GitSourceAnalysis", func(t *testing.T) {
+		//given
+		cpAuto := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    BuildTypeAuto,
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+			},
+		}
+		gsa := &devconsoleapi.GitSourceAnalysis{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-analysis",
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.GitSourceAnalysisSpec{
+				GitSourceRef: devconsoleapi.GitSourceRef{Name: "my-git-source"},
+			},
+			Status: devconsoleapi.GitSourceAnalysisStatus{
+				Analyzed: true,
+				BuildEnvStatistics: devconsoleapi.BuildEnvStats{
+					SortedLanguages: []string{"Java", "JavaScript"},
+					DetectedBuildTypes: []devconsoleapi.DetectedBuildType{
+						{Language: "Java", Name: "maven", DetectedFiles: []string{"pom.xml"}},
+						{Language: "JavaScript", Name: "nodejs", DetectedFiles: []string{"package.json"}},
+					},
+				},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpAuto, gsa)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile is failing")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, instance))
+		require.Equal(t, "nodejs", instance.Status.BuildType, "the first detected build type having a builder image should be chosen")
+		requireCondition(t, instance, ConditionBuildTypeResolved, corev1.ConditionTrue, ReasonBuildTypeDetected)
+		require.Contains(t, getCondition(instance, ConditionBuildTypeResolved).Message, "package.json")
+		require.Contains(t, getCondition(instance, ConditionBuildTypeResolved).Message, "[maven] have no builder image")
+
+		require.Error(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, &devconsoleapi.GitSourceAnalysis{}), "the existing gitsourceanalysis should be reused")
+		isBuilder := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "nodejs"}, isBuilder), "builder imagestream is not created")
+		bc := &buildv1.BuildConfig{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, bc), "build config is not created")
+		require.Equal(t, "nodejs:latest", bc.Spec.CommonSpec.Strategy.SourceStrategy.From.Name)
+
+		//given none of the detected build types has a builder image
+		gsa.Status.BuildEnvStatistics.DetectedBuildTypes = gsa.Status.BuildEnvStatistics.DetectedBuildTypes[:1]
+		cl = fake.NewFakeClient(gs, cpAuto, gsa)
+		r = &ReconcileComponent{client: cl, scheme: s}
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile should not be requeued without supported build type")
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.TODO(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuildTypeResolved, corev1.ConditionFalse, ReasonBuildTypeNotSupported)
+	})
+
 	t.Run("with ReconcileComponent CR without codebases", func(t *testing.T) {
 		//given
 		objs := []runtime.Object{
@@ -646,35 +1042,1841 @@ func TestComponentController(t *testing.T) {
 		require.Equal(t, dc.Spec.Template.Spec.Containers[0].Ports[0].Name, "8080-tcp")
 
 	})
-}
 
-func fakeImageStreamImage(imageName string, ports []string, containerConfig string) *imagev1.ImageStreamImage {
-	exposedPorts := make(map[string]struct{})
-	var s struct{}
-	for _, port := range ports {
-		exposedPorts[port] = s
-	}
-	builderImage := &imagev1.ImageStreamImage{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("%s@sha256:9579a93ee", imageName),
-			Namespace: "openshift",
-		},
-		Image: imagev1.Image{
+	t.Run("with ReconcileComponent CR using a builder version", func(t *testing.T) {
+		//given
+		cpWithVersion := &devconsoleapi.Component{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: "sha256:9579a93ee",
+				Name:      Name,
+				Namespace: Namespace,
 			},
-			DockerImageMetadata: runtime.RawExtension{
-				Object: &dockerapiv10.DockerImage{
-					ContainerConfig: dockerapiv10.DockerConfig{
-						ExposedPorts: exposedPorts,
-					},
-				},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs:10",
+				GitSourceRef: "my-git-source",
 			},
-			DockerImageReference: fmt.Sprintf("docker.io/centos/%s-36-centos7@sha256:9579a93ee", imageName),
-		},
-	}
-	if containerConfig != "" {
-		(*builderImage).Image.DockerImageMetadata.Raw = []byte(containerConfig)
-	}
-	return builderImage
+		}
+		isNodejs := &imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nodejs",
+				Namespace: "openshift",
+			},
+			Status: imagev1.ImageStreamStatus{
+				Tags: []imagev1.NamedTagEventList{{
+					Tag:   "latest",
+					Items: []imagev1.TagEvent{{Image: "sha256:9579a93ee"}},
+				}, {
+					Tag:   "10",
+					Items: []imagev1.TagEvent{{Image: "sha256:1f0ae3b21"}},
+				}},
+			},
+		}
+		isi := fakeImageStreamImage("nodejs", []string{"3000/tcp"}, "")
+		isi.Name = "nodejs@sha256:1f0ae3b21"
+		cl := fake.NewFakeClient(gs, cpWithVersion, isNodejs)
+		clImage := fakeimage.NewSimpleClientset(isi)
+		r := &ReconcileComponent{client: cl, scheme: s, imageClient: clImage.ImageV1()}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		bc := &buildv1.BuildConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, bc))
+		require.Equal(t, "nodejs:10", bc.Spec.CommonSpec.Strategy.SourceStrategy.From.Name, "builder image tag should be the builder version")
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Equal(t, int32(3000), dc.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort, "port should be taken from the builder version")
+
+		//given a version the builder does not provide
+		cpWithVersion.Spec.BuildType = "nodejs:12"
+		cl = fake.NewFakeClient(gs, cpWithVersion, isNodejs)
+		r = &ReconcileComponent{client: cl, scheme: s, imageClient: clImage.ImageV1()}
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.Error(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuilderImageReady, corev1.ConditionFalse, ReasonBuilderTagNotFound)
+		require.Contains(t, getCondition(instance, ConditionBuilderImageReady).Message, "available tags: [10, latest]")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &buildv1.BuildConfig{}), "build config should not be created")
+	})
+
+	t.Run("with ReconcileComponent CR waiting for the import of its builder image", func(t *testing.T) {
+		//given
+		cpImporting := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+			},
+		}
+		isi := fakeImageStreamImage("nodejs", []string{"3000/tcp"}, "")
+		isi.Namespace = Namespace
+		cl := fake.NewFakeClient(gs, cpImporting)
+		clImage := fakeimage.NewSimpleClientset(isi)
+		r := &ReconcileComponent{client: cl, scheme: s, imageClient: clImage.ImageV1()}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile should wait for the import of the builder image")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuilderImageReady, corev1.ConditionUnknown, ReasonBuilderImageImportPending)
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &buildv1.BuildConfig{}), "build config should be created, its image change trigger starts the build")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{}), "deployment config should wait for the ports of the builder image")
+
+		//given the builder image is imported
+		builderIS := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "nodejs"}, builderIS))
+		builderIS.Status.Tags = []imagev1.NamedTagEventList{{Tag: "latest", Items: []imagev1.TagEvent{{Image: "sha256:9579a93ee"}}}}
+		require.NoError(t, cl.Update(context.Background(), builderIS))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuilderImageReady, corev1.ConditionTrue, ReasonBuilderImageFound)
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc), "deployment config is not created")
+		require.Equal(t, int32(3000), dc.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort, "port should be read from the builder image")
+
+		//given the import of the builder image fails
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "nodejs"}, builderIS))
+		builderIS.Status.Tags = []imagev1.NamedTagEventList{{Tag: "latest", Conditions: []imagev1.TagEventCondition{{
+			Type:    imagev1.ImportSuccess,
+			Status:  corev1.ConditionFalse,
+			Message: "image not found",
+		}}}}
+		require.NoError(t, cl.Update(context.Background(), builderIS))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "failed import should not be requeued")
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuilderImageReady, corev1.ConditionFalse, ReasonImageImportFailed)
+		require.Contains(t, getCondition(instance, ConditionBuilderImageReady).Message, "image not found")
+	})
+
+	t.Run("with ReconcileComponent CR with a Dockerfile detected by the GitSourceAnalysis", func(t *testing.T) {
+		//given
+		cpDocker := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				GitSourceRef: "my-git-source",
+			},
+		}
+		gsa := &devconsoleapi.GitSourceAnalysis{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-analysis",
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.GitSourceAnalysisSpec{
+				GitSourceRef: devconsoleapi.GitSourceRef{Name: "my-git-source"},
+			},
+			Status: devconsoleapi.GitSourceAnalysisStatus{
+				Analyzed: true,
+				BuildEnvStatistics: devconsoleapi.BuildEnvStats{
+					DetectedBuildTypes: []devconsoleapi.DetectedBuildType{
+						{Language: "Dockerfile", Name: "docker", DetectedFiles: []string{"Dockerfile"}},
+						{Language: "JavaScript", Name: "nodejs", DetectedFiles: []string{"package.json"}},
+					},
+				},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpDocker, gsa)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, BuildStrategyDocker, instance.Status.BuildStrategy, "docker build strategy should be detected")
+		require.Empty(t, instance.Status.BuildType, "docker build strategy does not use a build type")
+		requireCondition(t, instance, ConditionBuildTypeResolved, corev1.ConditionTrue, ReasonDockerfileDetected)
+		require.Nil(t, getCondition(instance, ConditionBuilderImageReady), "docker build strategy does not use a builder image")
+		require.Error(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "nodejs"}, &imagev1.ImageStream{}), "builder imagestream should not be created")
+		bc := &buildv1.BuildConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, bc), "build config is not created")
+		require.NotNil(t, bc.Spec.Strategy.DockerStrategy, "build config should use the docker strategy")
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc), "deployment config is not created")
+		require.Equal(t, int32(8080), dc.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort, "default port should be used")
+
+		//given the component is not built anymore
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Spec.BuildStrategy = BuildStrategyNone
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, BuildStrategyNone, instance.Status.BuildStrategy)
+		requireCondition(t, instance, ConditionBuildTypeResolved, corev1.ConditionTrue, ReasonBuildTypeNotRequired)
+		require.Nil(t, getCondition(instance, ConditionBuildSucceeded), "build condition should not be set when the component is not built")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &buildv1.BuildConfig{}), "build config should be deleted")
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{}), "deployment config should be kept")
+	})
+
+	t.Run("with ReconcileComponent CR deploying an external image", func(t *testing.T) {
+		//given
+		cpImage := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				Image:   "quay.io/acme/frontend:1.0",
+				Exposed: true,
+			},
+		}
+		isi := fakeImageStreamImage(Name, []string{"8080/tcp"}, "")
+		isi.Namespace = Namespace
+		cl := fake.NewFakeClient(cpImage)
+		clImage := fakeimage.NewSimpleClientset(isi)
+		r := &ReconcileComponent{client: cl, scheme: s, imageClient: clImage.ImageV1()}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "reconcile should wait for the image import")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionSourceResolved, corev1.ConditionUnknown, ReasonImageImportPending)
+		is := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, is), "output imagestream is not created")
+		require.Equal(t, 1, len(is.Spec.Tags), "output imagestream should import the image")
+		require.Equal(t, "DockerImage", is.Spec.Tags[0].From.Kind)
+		require.Equal(t, "quay.io/acme/frontend:1.0", is.Spec.Tags[0].From.Name)
+		require.True(t, is.Spec.Tags[0].ImportPolicy.Scheduled, "image import should be scheduled")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{}), "deployment config should not be created before the import")
+
+		//given the image is imported
+		is.Status.Tags = []imagev1.NamedTagEventList{{Tag: "latest", Items: []imagev1.TagEvent{{Image: "sha256:9579a93ee"}}}}
+		require.NoError(t, cl.Update(context.Background(), is))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionSourceResolved, corev1.ConditionTrue, ReasonImageImported)
+		require.Nil(t, getCondition(instance, ConditionBuildSucceeded), "build condition should not be set for an image-only component")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &buildv1.BuildConfig{}), "build config should not be created")
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc), "deployment config is not created")
+		require.Equal(t, "8080-tcp", dc.Spec.Template.Spec.Containers[0].Ports[0].Name, "port should be detected from the imported image")
+		svc := &corev1.Service{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, svc), "service is not created")
+		require.Equal(t, int32(8080), svc.Spec.Ports[0].Port)
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &routev1.Route{}), "route is not created")
+
+		//given the import fails
+		is = &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, is))
+		is.Status.Tags = []imagev1.NamedTagEventList{{Tag: "latest", Conditions: []imagev1.TagEventCondition{{
+			Type:    imagev1.ImportSuccess,
+			Status:  corev1.ConditionFalse,
+			Message: "image not found",
+		}}}}
+		require.NoError(t, cl.Update(context.Background(), is))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionSourceResolved, corev1.ConditionFalse, ReasonImageImportFailed)
+		requireCondition(t, instance, ConditionReady, corev1.ConditionFalse, ReasonImageImportFailed)
+	})
+
+	t.Run("with ReconcileComponent CR deploying an external image exposing a privileged port", func(t *testing.T) {
+		//given
+		cpImage := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				Image:   "docker.io/library/nginx:1.17",
+				Exposed: true,
+			},
+		}
+		isi := fakeImageStreamImage(Name, []string{"80/tcp", "443/tcp"}, "")
+		isi.Namespace = Namespace
+		cl := fake.NewFakeClient(cpImage)
+		clImage := fakeimage.NewSimpleClientset(isi)
+		r := &ReconcileComponent{client: cl, scheme: s, imageClient: clImage.ImageV1()}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		is := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, is))
+		is.Status.Tags = []imagev1.NamedTagEventList{{Tag: "latest", Items: []imagev1.TagEvent{{Image: "sha256:9579a93ee"}}}}
+		require.NoError(t, cl.Update(context.Background(), is))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		svc := &corev1.Service{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, svc), "service is not created")
+		require.Equal(t, 2, len(svc.Spec.Ports))
+		require.Equal(t, int32(80), svc.Spec.Ports[0].Port)
+		require.Equal(t, int32(443), svc.Spec.Ports[1].Port)
+		route := &routev1.Route{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, route), "route is not created")
+		require.Equal(t, "80-tcp", route.Spec.Port.TargetPort.String(), "route should target the lowest port")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.NotEqual(t, ReasonPortInvalid, getCondition(instance, ConditionDeploymentAvailable).Reason)
+	})
+
+	t.Run("with ReconcileComponent CR deployed by a Deployment", func(t *testing.T) {
+		//given
+		cpDeployment := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name":     Name,
+					"app.kubernetes.io/instance": "mycomp-1",
+				},
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:      "nodejs",
+				GitSourceRef:   "my-git-source",
+				Port:           8080,
+				DeploymentKind: DeploymentKindDeployment,
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpDeployment)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{}), "deployment config should not be created")
+		d := &k8sappsv1.Deployment{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, d), "deployment is not created")
+		require.Equal(t, `[{"from":{"kind":"ImageStreamTag","name":"MyComp:latest"},"fieldPath":"spec.template.spec.containers[?(@.name==\"MyComp\")].image"}]`, d.Annotations[imageTriggersAnnotation], "deployment image should be triggered by the output imagestream")
+		require.Equal(t, Name, d.Spec.Template.Spec.Containers[0].Name)
+		require.Equal(t, int32(8080), d.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort)
+		require.Equal(t, Name, d.Spec.Selector.MatchLabels["app"])
+		svc := &corev1.Service{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, svc), "service is not created")
+		require.Equal(t, d.Spec.Selector.MatchLabels, svc.Spec.Selector, "service should select the pods of the deployment")
+		require.True(t, labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(d.Spec.Template.Labels)), "service should select the pod template labels")
+
+		//given the deployment is available
+		d.Status.Replicas = 1
+		d.Status.AvailableReplicas = 1
+		d.Status.Conditions = []k8sappsv1.DeploymentCondition{{Type: k8sappsv1.DeploymentAvailable, Status: corev1.ConditionTrue}}
+		require.NoError(t, cl.Update(context.Background(), d))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionTrue, ReasonReplicasAvailable)
+		require.Equal(t, devconsoleapi.PhaseDeployed, instance.Status.Phase)
+
+		//given the deployment does not progress
+		d.Status.Conditions = []k8sappsv1.DeploymentCondition{{Type: k8sappsv1.DeploymentProgressing, Status: corev1.ConditionFalse, Message: "ReplicaSet has timed out progressing"}}
+		require.NoError(t, cl.Update(context.Background(), d))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonDeploymentFailed)
+
+		//given the component switches back to a DeploymentConfig
+		instance.Spec.DeploymentKind = DeploymentKindDeploymentConfig
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{}), "deployment config is not created")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &k8sappsv1.Deployment{}), "deployment should be deleted")
+	})
+
+	t.Run("with ReconcileComponent CR switching to a Knative Service on a cluster without Knative", func(t *testing.T) {
+		//given
+		cpExposed := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+				Exposed:      true,
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpExposed)
+		// no API group is registered, Knative Serving is not installed
+		r := &ReconcileComponent{client: cl, scheme: s, restMapper: meta.NewDefaultRESTMapper(nil)}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Spec.DeploymentKind = DeploymentKindKnativeService
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonKnativeNotInstalled)
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{}), "deployment config should not be deleted")
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &corev1.Service{}), "service should not be deleted")
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, &routev1.Route{}), "route should not be deleted")
+	})
+
+	t.Run("with ReconcileComponent CR declaring a privileged port", func(t *testing.T) {
+		//given
+		cpPrivileged := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         80,
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpPrivileged)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "invalid port should not be requeued")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonPortInvalid)
+		require.Contains(t, getCondition(instance, ConditionDeploymentAvailable).Message, "port 80 is out of range [1024-65535]")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{}), "deployment config should not be created")
+	})
+
+	t.Run("with ReconcileComponent CR using a build type without builder image", func(t *testing.T) {
+		//given
+		cpUnknown := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "cobol",
+				GitSourceRef: "my-git-source",
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpUnknown)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "missing builder image should not be requeued")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuilderImageReady, corev1.ConditionFalse, ReasonBuilderImageNotFound)
+		require.Contains(t, getCondition(instance, ConditionBuilderImageReady).Message, "builder image cobol is found neither in namespace openshift nor in the builder image catalog")
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &buildv1.BuildConfig{}), "build config should not be created")
+	})
+
+	t.Run("with ReconcileComponent CR autoscaled by a HorizontalPodAutoscaler", func(t *testing.T) {
+		//given
+		minReplicas := int32(2)
+		memoryTarget := int32(70)
+		cpAutoscaled := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+				Autoscaling: &devconsoleapi.ComponentAutoscaling{
+					MinReplicas:                       &minReplicas,
+					MaxReplicas:                       5,
+					TargetMemoryUtilizationPercentage: &memoryTarget,
+				},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpAutoscaled)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc), "deployment config is not created")
+		require.Equal(t, minReplicas, dc.Spec.Replicas, "deployment config should start with the minimum replicas")
+		hpa := &autoscalingv2beta1.HorizontalPodAutoscaler{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, hpa), "horizontal pod autoscaler is not created")
+		require.Equal(t, DeploymentKindDeploymentConfig, hpa.Spec.ScaleTargetRef.Kind)
+		require.Equal(t, Name, hpa.Spec.ScaleTargetRef.Name)
+		require.Equal(t, minReplicas, *hpa.Spec.MinReplicas)
+		require.Equal(t, int32(5), hpa.Spec.MaxReplicas)
+		require.Len(t, hpa.Spec.Metrics, 1, "only the memory target should be set")
+		require.Equal(t, corev1.ResourceMemory, hpa.Spec.Metrics[0].Resource.Name)
+		require.Equal(t, memoryTarget, *hpa.Spec.Metrics[0].Resource.TargetAverageUtilization)
+
+		//given the autoscaler scales the deployment config up
+		dc.Spec.Replicas = 4
+		dc.Status.Replicas = 2
+		dc.Status.AvailableReplicas = 2
+		require.NoError(t, cl.Update(context.Background(), dc))
+		hpa.Status.DesiredReplicas = 4
+		require.NoError(t, cl.Update(context.Background(), hpa))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Equal(t, int32(4), dc.Spec.Replicas, "replicas set by the autoscaler should be kept")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, devconsoleapi.PhaseDeploying, instance.Status.Phase)
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonScalingUp)
+
+		//given the desired replicas are available
+		dc.Status.Replicas = 4
+		dc.Status.AvailableReplicas = 4
+		require.NoError(t, cl.Update(context.Background(), dc))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, devconsoleapi.PhaseDeployed, instance.Status.Phase)
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionTrue, ReasonReplicasAvailable)
+
+		//given the component is not autoscaled anymore
+		replicas := int32(3)
+		instance.Spec.Autoscaling = nil
+		instance.Spec.Replicas = &replicas
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.Error(t, cl.Get(context.Background(), req.NamespacedName, &autoscalingv2beta1.HorizontalPodAutoscaler{}), "horizontal pod autoscaler should be deleted")
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Equal(t, replicas, dc.Spec.Replicas, "replicas of the spec should be set")
+	})
+
+	t.Run("with ReconcileComponent CR controlling its rollouts", func(t *testing.T) {
+		//given
+		timeoutSeconds := int64(120)
+		maxUnavailable := intstr.FromInt(0)
+		cpRollout := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+				DeploymentStrategy: &devconsoleapi.ComponentDeploymentStrategy{
+					Type:           DeploymentStrategyRolling,
+					MaxUnavailable: &maxUnavailable,
+					TimeoutSeconds: &timeoutSeconds,
+				},
+			},
+		}
+		previousVersion := &corev1.ReplicationController{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name + "-1",
+				Namespace: Namespace,
+			},
+			Spec: corev1.ReplicationControllerSpec{
+				Template: &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: Name, Image: "172.30.1.1:5000/test-project/MyComp@sha256:1111"}},
+					},
+				},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpRollout, previousVersion)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Equal(t, appsv1.DeploymentStrategyTypeRolling, dc.Spec.Strategy.Type, "rolling strategy should be selected")
+		require.Equal(t, maxUnavailable, *dc.Spec.Strategy.RollingParams.MaxUnavailable)
+		require.Equal(t, intstr.FromString("25%"), *dc.Spec.Strategy.RollingParams.MaxSurge)
+		require.Equal(t, timeoutSeconds, *dc.Spec.Strategy.RollingParams.TimeoutSeconds)
+		require.False(t, dc.Spec.Paused)
+
+		//given the deployment config is rolled out
+		dc.Status.LatestVersion = 2
+		dc.Status.Replicas = 1
+		dc.Status.ReadyReplicas = 1
+		dc.Status.AvailableReplicas = 1
+		require.NoError(t, cl.Update(context.Background(), dc))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, int64(2), instance.Status.LatestVersion)
+		require.Equal(t, int32(1), instance.Status.ReadyReplicas)
+
+		//given the rollouts are paused and the component switches to the recreate strategy
+		instance.Annotations = map[string]string{pauseRolloutAnnotation: "true"}
+		instance.Spec.DeploymentStrategy.Type = DeploymentStrategyRecreate
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.True(t, dc.Spec.Paused, "deployment config should be paused")
+		require.Equal(t, appsv1.DeploymentStrategyTypeRecreate, dc.Spec.Strategy.Type)
+		require.Nil(t, dc.Spec.Strategy.RollingParams)
+		require.Equal(t, timeoutSeconds, *dc.Spec.Strategy.RecreateParams.TimeoutSeconds)
+
+		//given the component is rolled back to a missing version
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Annotations = map[string]string{rollbackToAnnotation: "5"}
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonRollbackInvalid)
+
+		//given the component is rolled back to its first version
+		instance.Annotations = map[string]string{rollbackToAnnotation: "1"}
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.False(t, dc.Spec.Paused, "deployment config should be resumed")
+		require.Equal(t, "172.30.1.1:5000/test-project/MyComp@sha256:1111", dc.Spec.Template.Spec.Containers[0].Image, "image of the first version should be deployed")
+		for _, trigger := range dc.Spec.Triggers {
+			if trigger.ImageChangeParams != nil {
+				require.False(t, trigger.ImageChangeParams.Automatic, "image change trigger should be disabled during the rollback")
+			}
+		}
+	})
+
+	t.Run("with ReconcileComponent CR with persistent volumes", func(t *testing.T) {
+		//given
+		storageClass := "gp2"
+		cpVolumes := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+				Volumes: []devconsoleapi.ComponentVolume{{
+					Name:         "data",
+					Size:         resource.MustParse("1Gi"),
+					StorageClass: &storageClass,
+					MountPath:    "/var/lib/data",
+				}, {
+					Name:       "shared",
+					Size:       resource.MustParse("5Gi"),
+					AccessMode: corev1.ReadWriteMany,
+					MountPath:  "/var/lib/shared",
+				}},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpVolumes)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		pvc := &corev1.PersistentVolumeClaim{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-data", Namespace: Namespace}, pvc), "persistent volume claim is not created")
+		require.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}, pvc.Spec.AccessModes, "volume should be ReadWriteOnce by default")
+		require.Equal(t, storageClass, *pvc.Spec.StorageClassName)
+		require.Equal(t, resource.MustParse("1Gi"), pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-shared", Namespace: Namespace}, pvc), "persistent volume claim is not created")
+		require.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, pvc.Spec.AccessModes)
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Len(t, dc.Spec.Template.Spec.Volumes, 2)
+		require.Equal(t, Name+"-data", dc.Spec.Template.Spec.Volumes[0].PersistentVolumeClaim.ClaimName)
+		require.Equal(t, []corev1.VolumeMount{{Name: "data", MountPath: "/var/lib/data"}, {Name: "shared", MountPath: "/var/lib/shared"}}, dc.Spec.Template.Spec.Containers[0].VolumeMounts)
+		require.Equal(t, appsv1.DeploymentStrategyTypeRecreate, dc.Spec.Strategy.Type, "component with a ReadWriteOnce volume should be recreated")
+
+		//given the ReadWriteOnce volume is removed and the shared one grows
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Spec.Volumes = instance.Spec.Volumes[1:]
+		instance.Spec.Volumes[0].Size = resource.MustParse("10Gi")
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.Error(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-data", Namespace: Namespace}, &corev1.PersistentVolumeClaim{}), "claim of the removed volume should be deleted")
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-shared", Namespace: Namespace}, pvc))
+		require.Equal(t, resource.MustParse("10Gi"), pvc.Spec.Resources.Requests[corev1.ResourceStorage], "claim should be expanded")
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Len(t, dc.Spec.Template.Spec.Volumes, 1)
+		require.Len(t, dc.Spec.Template.Spec.Containers[0].VolumeMounts, 1)
+		require.Equal(t, appsv1.DeploymentStrategyTypeRecreate, dc.Spec.Strategy.Type, "recreate strategy should be the default")
+	})
+
+	t.Run("with ReconcileComponent CR publishing its webhook URL", func(t *testing.T) {
+		//given
+		cpWebhook := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpWebhook)
+		r := &ReconcileComponent{client: cl, scheme: s, apiServerURL: "https://api.example.com:6443/"}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		secret := &corev1.Secret{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-webhook", Namespace: Namespace}, secret), "webhook secret is not created")
+		webhookSecret := string(secret.Data[webhookSecretKey])
+		require.NotEmpty(t, webhookSecret)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, "https://api.example.com:6443/apis/build.openshift.io/v1/namespaces/"+Namespace+"/buildconfigs/"+Name+"/webhooks/"+webhookSecret+"/generic", instance.Status.WebhookURL)
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-webhook", Namespace: Namespace}, secret))
+		require.Equal(t, webhookSecret, string(secret.Data[webhookSecretKey]), "webhook secret should not change")
+
+		//given the component is not built anymore
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Spec.BuildStrategy = BuildStrategyNone
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.Error(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-webhook", Namespace: Namespace}, &corev1.Secret{}), "webhook secret should be deleted")
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Empty(t, instance.Status.WebhookURL)
+	})
+
+	t.Run("with ReconcileComponent CR sharing the name of a Secret without webhook secret", func(t *testing.T) {
+		//given
+		cpWebhook := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+			},
+		}
+		otherSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name + "-webhook",
+				Namespace: Namespace,
+			},
+			Data: map[string][]byte{
+				"token": []byte("not-a-webhook-secret"),
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpWebhook, otherSecret)
+		r := &ReconcileComponent{client: cl, scheme: s, apiServerURL: "https://api.example.com:6443"}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err, "secret without webhook secret should not be requeued")
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuildSucceeded, corev1.ConditionFalse, ReasonWebhookSecretInvalid)
+		require.Empty(t, instance.Status.WebhookURL, "webhook URL should not be published")
+		secret := &corev1.Secret{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Name: Name + "-webhook", Namespace: Namespace}, secret))
+		require.Equal(t, otherSecret.Data, secret.Data, "secret of someone else should be left untouched")
+		require.Empty(t, secret.OwnerReferences, "secret of someone else should not be owned by the component")
+
+		//given the webhook secret is added to the Secret
+		secret.Data[webhookSecretKey] = []byte("my-webhook-secret")
+		require.NoError(t, cl.Update(context.Background(), secret))
+		requests := newSecretMapper(cl)(handler.MapObject{Meta: secret, Object: secret})
+		require.Equal(t, []reconcile.Request{req}, requests, "component should be reconciled when its webhook secret changes")
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, "https://api.example.com:6443/apis/build.openshift.io/v1/namespaces/"+Namespace+"/buildconfigs/"+Name+"/webhooks/my-webhook-secret/generic", instance.Status.WebhookURL)
+	})
+
+	t.Run("with ReconcileComponent CR updated after resources creation", func(t *testing.T) {
+		//given
+		cpToUpdate := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+				Exposed:      true,
+			},
+		}
+		objs := []runtime.Object{
+			gs,
+			cpToUpdate,
+		}
+		// Create a fake client to mock API calls.
+		cl := fake.NewFakeClient(objs...)
+
+		// Create a ReconcileComponent object with the scheme and fake client.
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+
+		// fields set by other controllers
+		svc := &corev1.Service{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, svc))
+		svc.Spec.ClusterIP = "172.30.0.10"
+		svc.Labels["owner"] = "someone-else"
+		require.NoError(t, cl.Update(context.Background(), svc))
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, dc))
+		dc.Spec.Template.Spec.Containers[0].Image = "172.30.1.1:5000/test-project/MyComp@sha256:9579a93ee"
+		require.NoError(t, cl.Update(context.Background(), dc))
+
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Spec.Port = Port
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+
+		svc = &corev1.Service{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, svc))
+		require.Equal(t, 1, len(svc.Spec.Ports), "service is using one port")
+		require.Equal(t, int32(Port), svc.Spec.Ports[0].Port, "service port should be updated to 3000")
+		require.Equal(t, "172.30.0.10", svc.Spec.ClusterIP, "service cluster ip should not be changed")
+		require.Equal(t, "someone-else", svc.Labels["owner"], "service labels not set by the operator should be kept")
+
+		dc = &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, dc))
+		require.Equal(t, int32(Port), dc.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort, "container port should be updated to 3000")
+		require.Equal(t, "172.30.1.1:5000/test-project/MyComp@sha256:9579a93ee", dc.Spec.Template.Spec.Containers[0].Image, "container image resolved by the trigger should be kept")
+
+		rte := &routev1.Route{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: Name}, rte))
+		require.Equal(t, "3000-tcp", rte.Spec.Port.TargetPort.StrVal, "route target port should be updated to 3000")
+	})
+
+	t.Run("with ReconcileComponent CR keeping the volumes and environment added by others", func(t *testing.T) {
+		//given
+		cpWithEnv := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+				Env: []corev1.EnvVar{
+					{Name: "NODE_ENV", Value: "production"},
+					{Name: "LOG_LEVEL", Value: "info"},
+				},
+				Volumes: []devconsoleapi.ComponentVolume{{
+					Name:       "data",
+					Size:       resource.MustParse("1Gi"),
+					AccessMode: corev1.ReadWriteMany,
+					MountPath:  "/var/data",
+				}},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpWithEnv)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+
+		// volume and environment injected by another controller
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		dc.Spec.Template.Spec.Volumes = append(dc.Spec.Template.Spec.Volumes, corev1.Volume{
+			Name:         "istio-certs",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		})
+		container := &dc.Spec.Template.Spec.Containers[0]
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: "istio-certs", MountPath: "/etc/certs"})
+		container.Env = append(container.Env, corev1.EnvVar{Name: "ISTIO_META_POD_NAME", Value: "injected"})
+		require.NoError(t, cl.Update(context.Background(), dc))
+
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Spec.Env = []corev1.EnvVar{{Name: "NODE_ENV", Value: "development"}}
+		instance.Spec.Volumes = nil
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		dc = &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Equal(t, []corev1.Volume{{
+			Name:         "istio-certs",
+			VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+		}}, dc.Spec.Template.Spec.Volumes, "only the volume removed from the component should be removed")
+		container = &dc.Spec.Template.Spec.Containers[0]
+		require.Equal(t, []corev1.VolumeMount{{Name: "istio-certs", MountPath: "/etc/certs"}}, container.VolumeMounts)
+		require.Equal(t, []corev1.EnvVar{
+			{Name: "NODE_ENV", Value: "development"},
+			{Name: "ISTIO_META_POD_NAME", Value: "injected"},
+		}, container.Env, "environment variables added by others should be kept")
+		require.Equal(t, "NODE_ENV", dc.Spec.Template.Annotations[ownedEnvAnnotation])
+		require.NotContains(t, dc.Spec.Template.Annotations, ownedVolumesAnnotation)
+	})
+
+	t.Run("with ReconcileComponent CR reporting status conditions", func(t *testing.T) {
+		//given
+		cpWithConditions := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/name": Name,
+				},
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+			},
+		}
+		objs := []runtime.Object{
+			gs,
+			cpWithConditions,
+		}
+		// Create a fake client to mock API calls.
+		cl := fake.NewFakeClient(objs...)
+
+		// Create a ReconcileComponent object with the scheme and fake client.
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionSourceResolved, corev1.ConditionTrue, ReasonGitSourceFound)
+		requireCondition(t, instance, ConditionBuilderImageReady, corev1.ConditionUnknown, ReasonBuilderImageImportPending)
+		requireCondition(t, instance, ConditionBuildSucceeded, corev1.ConditionUnknown, ReasonBuildPending)
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionUnknown, ReasonDeploymentPending)
+		requireCondition(t, instance, ConditionReady, corev1.ConditionFalse, ReasonBuilderImageImportPending)
+		require.Nil(t, getCondition(instance, ConditionRouteAdmitted), "route condition should not be set when the component is not exposed")
+
+		//given the builder image is imported, the image is built and deployed
+		builderIS := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "nodejs"}, builderIS))
+		builderIS.Status.Tags = []imagev1.NamedTagEventList{{Tag: "latest", Items: []imagev1.TagEvent{{Image: "sha256:1f0ae3b21"}}}}
+		require.NoError(t, cl.Update(context.Background(), builderIS))
+		bc := &buildv1.BuildConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, bc))
+		bc.Status.LastVersion = 1
+		require.NoError(t, cl.Update(context.Background(), bc))
+		is := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, is))
+		is.Status.Tags = []imagev1.NamedTagEventList{{Tag: "latest", Items: []imagev1.TagEvent{{Image: "sha256:9579a93ee"}}}}
+		require.NoError(t, cl.Update(context.Background(), is))
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		dc.Status.Replicas = 1
+		dc.Status.AvailableReplicas = 1
+		require.NoError(t, cl.Update(context.Background(), dc))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuilderImageReady, corev1.ConditionTrue, ReasonBuilderImageFound)
+		requireCondition(t, instance, ConditionBuildSucceeded, corev1.ConditionTrue, ReasonImageBuilt)
+		requireCondition(t, instance, ConditionDeploymentAvailable, corev1.ConditionTrue, ReasonReplicasAvailable)
+		requireCondition(t, instance, ConditionReady, corev1.ConditionTrue, ReasonAllConditionsMet)
+		require.Equal(t, devconsoleapi.PhaseDeployed, instance.Status.Phase)
+	})
+
+	t.Run("with ReconcileComponent CR reporting its last build", func(t *testing.T) {
+		//given
+		cpBuilds := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+			},
+		}
+		newBuild := func(number int, phase buildv1.BuildPhase) *buildv1.Build {
+			return &buildv1.Build{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        fmt.Sprintf("%s-%d", Name, number),
+					Namespace:   Namespace,
+					Labels:      map[string]string{buildv1.BuildConfigLabel: Name},
+					Annotations: map[string]string{buildv1.BuildNumberAnnotation: fmt.Sprint(number)},
+				},
+				Status: buildv1.BuildStatus{Phase: phase},
+			}
+		}
+		failedBuild := newBuild(1, buildv1.BuildPhaseFailed)
+		failedBuild.Status.Reason = buildv1.StatusReasonFetchSourceFailed
+		failedBuild.Status.Message = "Failed to fetch the input source."
+		cl := fake.NewFakeClient(gs, cpBuilds, failedBuild)
+		r := &ReconcileComponent{client: cl, scheme: s, apiServerURL: "https://api.example.com:6443"}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		bc := &buildv1.BuildConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, bc))
+		bc.Status.LastVersion = 1
+		require.NoError(t, cl.Update(context.Background(), bc))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionBuildSucceeded, corev1.ConditionFalse, ReasonBuildFailed)
+		requireCondition(t, instance, ConditionBuildFailed, corev1.ConditionTrue, ReasonBuildFailed)
+		require.Contains(t, getCondition(instance, ConditionBuildFailed).Message, "FetchSourceFailed: Failed to fetch the input source.")
+		require.Contains(t, getCondition(instance, ConditionBuildSucceeded).Message, "FetchSourceFailed: Failed to fetch the input source.")
+		require.Contains(t, getCondition(instance, ConditionBuildSucceeded).Message, "https://api.example.com:6443/apis/build.openshift.io/v1/namespaces/"+Namespace+"/builds/"+Name+"-1/log")
+		require.NotNil(t, instance.Status.LastBuild)
+		require.Equal(t, Name+"-1", instance.Status.LastBuild.Name)
+		require.Equal(t, string(buildv1.BuildPhaseFailed), instance.Status.LastBuild.Phase)
+
+		//given a new build completes
+		completedBuild := newBuild(2, buildv1.BuildPhaseComplete)
+		completedBuild.Spec.Revision = &buildv1.SourceRevision{
+			Git: &buildv1.GitSourceRevision{
+				Commit:  "a1b2c3d",
+				Author:  buildv1.SourceControlUser{Name: "Jane Doe", Email: "jane@example.com"},
+				Message: "Fix the readiness probe",
+			},
+		}
+		startTime := metav1.Now()
+		completedBuild.Status.StartTimestamp = &startTime
+		completedBuild.Status.Output.To = &buildv1.BuildStatusOutputTo{ImageDigest: "sha256:9579a93ee"}
+		require.NoError(t, cl.Create(context.Background(), completedBuild))
+		bc.Status.LastVersion = 2
+		require.NoError(t, cl.Update(context.Background(), bc))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, Name+"-2", instance.Status.LastBuild.Name, "latest build should be reported")
+		require.Equal(t, string(buildv1.BuildPhaseComplete), instance.Status.LastBuild.Phase)
+		require.Equal(t, "a1b2c3d", instance.Status.LastBuild.Commit)
+		require.Equal(t, "Jane Doe", instance.Status.LastBuild.Author)
+		require.Equal(t, "Fix the readiness probe", instance.Status.LastBuild.Message)
+		require.Equal(t, "sha256:9579a93ee", instance.Status.LastBuild.ImageDigest)
+		require.NotNil(t, instance.Status.LastBuild.StartTime)
+		require.NotEqual(t, corev1.ConditionFalse, getCondition(instance, ConditionBuildSucceeded).Status, "build should not be failed anymore")
+		requireCondition(t, instance, ConditionBuildFailed, corev1.ConditionFalse, ReasonBuildNotFailed)
+	})
+
+	t.Run("with ReconcileComponent CR requesting a rebuild and a redeploy", func(t *testing.T) {
+		//given
+		cpRebuilt := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpRebuilt)
+		clBuild := fakebuild.NewSimpleClientset()
+		clBuild.PrependReactor("create", "buildconfigs", func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, &buildv1.Build{ObjectMeta: metav1.ObjectMeta{Name: Name + "-2", Namespace: Namespace}}, nil
+		})
+		r := &ReconcileComponent{client: cl, scheme: s, buildClient: clBuild.BuildV1()}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		bc := &buildv1.BuildConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, bc))
+		bc.Status.LastVersion = 1
+		require.NoError(t, cl.Update(context.Background(), bc))
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		instance.Annotations = map[string]string{rebuildAnnotation: "1", redeployAnnotation: "1"}
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.Len(t, clBuild.Actions(), 1, "a single build should be started")
+		action, ok := clBuild.Actions()[0].(clienttesting.CreateAction)
+		require.True(t, ok)
+		require.Equal(t, "instantiate", action.GetSubresource())
+		require.Equal(t, Name, action.GetObject().(*buildv1.BuildRequest).Name)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Equal(t, "1", instance.Status.ObservedRebuild)
+		require.Equal(t, "1", instance.Status.ObservedRedeploy)
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Equal(t, "1", dc.Spec.Template.Annotations[redeployAnnotation])
+
+		//when the same values are reconciled again
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		require.Len(t, clBuild.Actions(), 1, "the rebuild should not be started twice")
+	})
+
+	t.Run("with ReconcileComponent CR referencing a missing GitSource", func(t *testing.T) {
+		//given
+		cpWithoutGitSource := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "unknown-git-source",
+			},
+		}
+		cl := fake.NewFakeClient(cpWithoutGitSource)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.Error(t, err)
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		requireCondition(t, instance, ConditionSourceResolved, corev1.ConditionFalse, ReasonGitSourceNotFound)
+		requireCondition(t, instance, ConditionReady, corev1.ConditionFalse, ReasonGitSourceNotFound)
+	})
+
+	t.Run("with ReconcileComponent CR failing to list its DeploymentConfigs", func(t *testing.T) {
+		//given
+		cl := &failingListClient{Client: fake.NewFakeClient(gs, cp)}
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.Error(t, err, "reconcile should be requeued")
+		require.True(t, errors.IsServiceUnavailable(err))
+	})
+
+	t.Run("with ReconcileComponent CR using a build type of the builder image catalog", func(t *testing.T) {
+		//given
+		cpPython := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "python",
+				GitSourceRef: "my-git-source",
+			},
+		}
+		catalog := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      builderCatalogName,
+				Namespace: Namespace,
+			},
+			Data: map[string]string{
+				"python": `
+image: centos/python-36-centos7:latest
+versions:
+  "2.7": centos/python-27-centos7:latest
+ports:
+- 8000
+`,
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpPython, catalog)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+
+		//when
+		_, err := r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		isBuilder := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "python"}, isBuilder), "builder imagestream is not created")
+		require.Equal(t, 2, len(isBuilder.Spec.Tags), "imagestream builder should have a tag for latest and each version")
+		require.Equal(t, "latest", isBuilder.Spec.Tags[0].Name)
+		require.Equal(t, "centos/python-36-centos7:latest", isBuilder.Spec.Tags[0].From.Name)
+		require.Equal(t, "2.7", isBuilder.Spec.Tags[1].Name)
+		require.Equal(t, "centos/python-27-centos7:latest", isBuilder.Spec.Tags[1].From.Name)
+
+		dc := &appsv1.DeploymentConfig{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, dc))
+		require.Equal(t, int32(8000), dc.Spec.Template.Spec.Containers[0].Ports[0].ContainerPort, "port should be taken from the catalog")
+
+		//given the catalog is updated
+		catalog.Data["python"] = "image: centos/python-37-centos7:latest"
+		require.NoError(t, cl.Update(context.Background(), catalog))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		isBuilder = &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "python"}, isBuilder))
+		require.Equal(t, "centos/python-37-centos7:latest", isBuilder.Spec.Tags[0].From.Name, "builder imagestream should follow the catalog")
+	})
+
+	t.Run("with ReconcileComponent CR being deleted", func(t *testing.T) {
+		//given
+		cpToDelete := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name,
+				Namespace: Namespace,
+				UID:       "3a5e6ab0-7d38-11e9-9d37-0a580a800009",
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+				Exposed:      true,
+			},
+		}
+		otherCp := &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-comp",
+				Namespace: Namespace,
+				UID:       "8c2d5f9e-7d38-11e9-9d37-0a580a800009",
+			},
+			Spec: devconsoleapi.ComponentSpec{
+				BuildType:    "nodejs",
+				GitSourceRef: "my-git-source",
+				Port:         8080,
+			},
+		}
+		build := &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      Name + "-1",
+				Namespace: Namespace,
+				Labels: map[string]string{
+					buildv1.BuildConfigLabel: Name,
+				},
+			},
+		}
+		cl := fake.NewFakeClient(gs, cpToDelete, otherCp, build)
+		r := &ReconcileComponent{client: cl, scheme: s}
+		req := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      Name,
+				Namespace: Namespace,
+			},
+		}
+		otherReq := reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      otherCp.Name,
+				Namespace: Namespace,
+			},
+		}
+		_, err := r.Reconcile(req)
+		require.NoError(t, err)
+		_, err = r.Reconcile(otherReq)
+		require.NoError(t, err)
+
+		instance := &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.Contains(t, instance.Finalizers, componentFinalizer, "component should have the devconsole finalizer")
+		isBuilder := &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "nodejs"}, isBuilder))
+		require.Equal(t, 2, len(isBuilder.OwnerReferences), "builder imagestream should be owned by both components")
+
+		now := metav1.Now()
+		instance.DeletionTimestamp = &now
+		require.NoError(t, cl.Update(context.Background(), instance))
+
+		//when
+		_, err = r.Reconcile(req)
+
+		//then
+		require.NoError(t, err)
+		instance = &devconsoleapi.Component{}
+		require.NoError(t, cl.Get(context.Background(), req.NamespacedName, instance))
+		require.NotContains(t, instance.Finalizers, componentFinalizer, "finalizer should be removed once resources are deleted")
+
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, &buildv1.BuildConfig{})), "build config should be deleted")
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, &appsv1.DeploymentConfig{})), "deployment config should be deleted")
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, &corev1.Service{})), "service should be deleted")
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, &routev1.Route{})), "route should be deleted")
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), req.NamespacedName, &imagev1.ImageStream{})), "output imagestream should be deleted")
+		require.True(t, errors.IsNotFound(cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: build.Name}, &buildv1.Build{})), "build should be deleted")
+
+		isBuilder = &imagev1.ImageStream{}
+		require.NoError(t, cl.Get(context.Background(), types.NamespacedName{Namespace: Namespace, Name: "nodejs"}, isBuilder), "shared builder imagestream should be kept")
+		require.Equal(t, 1, len(isBuilder.OwnerReferences), "builder imagestream should only be owned by the remaining component")
+		require.Equal(t, otherCp.UID, isBuilder.OwnerReferences[0].UID)
+		require.NoError(t, cl.Get(context.Background(), otherReq.NamespacedName, &appsv1.DeploymentConfig{}), "resources of other components should be kept")
+	})
+}
+
+func requireCondition(t *testing.T, cp *devconsoleapi.Component, condType devconsoleapi.ComponentConditionType, status corev1.ConditionStatus, reason string) {
+	condition := getCondition(cp, condType)
+	require.NotNil(t, condition, "condition %s should be set", condType)
+	require.Equal(t, status, condition.Status, "condition %s has unexpected status", condType)
+	require.Equal(t, reason, condition.Reason, "condition %s has unexpected reason", condType)
+}
+
+// failingListClient fails to list any resource, as when the API server is not available.
+type failingListClient struct {
+	client.Client
+}
+
+func (c *failingListClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	return errors.NewServiceUnavailable("the server is currently unable to handle the request")
+}
+
+func fakeImageStreamImage(imageName string, ports []string, containerConfig string) *imagev1.ImageStreamImage {
+	exposedPorts := make(map[string]struct{})
+	var s struct{}
+	for _, port := range ports {
+		exposedPorts[port] = s
+	}
+	builderImage := &imagev1.ImageStreamImage{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s@sha256:9579a93ee", imageName),
+			Namespace: "openshift",
+		},
+		Image: imagev1.Image{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "sha256:9579a93ee",
+			},
+			DockerImageMetadata: runtime.RawExtension{
+				Object: &dockerapiv10.DockerImage{
+					ContainerConfig: dockerapiv10.DockerConfig{
+						ExposedPorts: exposedPorts,
+					},
+				},
+			},
+			DockerImageReference: fmt.Sprintf("docker.io/centos/%s-36-centos7@sha256:9579a93ee", imageName),
+		},
+	}
+	if containerConfig != "" {
+		(*builderImage).Image.DockerImageMetadata.Raw = []byte(containerConfig)
+	}
+	return builderImage
+}
+
+func TestNewBuildConfigFromGitSource(t *testing.T) {
+	cp := &devconsoleapi.Component{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name,
+			Namespace: Namespace,
+		},
+		Spec: devconsoleapi.ComponentSpec{
+			BuildType:    "nodejs",
+			GitSourceRef: "my-git-source",
+		},
+	}
+	builderIS := &imagev1.ImageStream{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nodejs",
+			Namespace: "openshift",
+		},
+	}
+	newGitSource := func(spec devconsoleapi.GitSourceSpec) *devconsoleapi.GitSource {
+		spec.URL = "https://somegit.con/myrepo"
+		spec.Ref = "master"
+		return &devconsoleapi.GitSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-git-source",
+				Namespace: Namespace,
+			},
+			Spec: spec,
+		}
+	}
+
+	t.Run("without context dir nor proxies", func(t *testing.T) {
+		bc := newBuildConfig(cp, builderIS, newGitSource(devconsoleapi.GitSourceSpec{}), nil)
+		require.Equal(t, "https://somegit.con/myrepo", bc.Spec.Source.Git.URI)
+		require.Equal(t, "master", bc.Spec.Source.Git.Ref)
+		require.Equal(t, buildv1.SourceBuildStrategyType, bc.Spec.Strategy.Type)
+		require.NotNil(t, bc.Spec.Strategy.SourceStrategy)
+		require.Empty(t, bc.Spec.Source.ContextDir, "build config should not have any context dir")
+		require.Nil(t, bc.Spec.Source.Git.HTTPProxy, "build config should not have any http proxy")
+		require.Nil(t, bc.Spec.Source.Git.HTTPSProxy, "build config should not have any https proxy")
+		require.Nil(t, bc.Spec.Source.Git.NoProxy, "build config should not have any no proxy")
+	})
+
+	t.Run("with context dir", func(t *testing.T) {
+		bc := newBuildConfig(cp, builderIS, newGitSource(devconsoleapi.GitSourceSpec{ContextDir: "services/frontend"}), nil)
+		require.Equal(t, "services/frontend", bc.Spec.Source.ContextDir)
+		require.Nil(t, bc.Spec.Source.Git.HTTPProxy, "build config should not have any http proxy")
+	})
+
+	t.Run("with http proxy only", func(t *testing.T) {
+		bc := newBuildConfig(cp, builderIS, newGitSource(devconsoleapi.GitSourceSpec{HttpProxy: "http://proxy.corp:3128"}), nil)
+		require.Empty(t, bc.Spec.Source.ContextDir)
+		require.NotNil(t, bc.Spec.Source.Git.HTTPProxy)
+		require.Equal(t, "http://proxy.corp:3128", *bc.Spec.Source.Git.HTTPProxy)
+		require.Nil(t, bc.Spec.Source.Git.HTTPSProxy, "build config should not have any https proxy")
+		require.Nil(t, bc.Spec.Source.Git.NoProxy, "build config should not have any no proxy")
+	})
+
+	t.Run("with all proxies", func(t *testing.T) {
+		bc := newBuildConfig(cp, builderIS, newGitSource(devconsoleapi.GitSourceSpec{
+			HttpProxy:  "http://proxy.corp:3128",
+			HttpsProxy: "https://proxy.corp:3129",
+			NoProxy:    ".corp,localhost",
+		}), nil)
+		require.Equal(t, "http://proxy.corp:3128", *bc.Spec.Source.Git.HTTPProxy)
+		require.Equal(t, "https://proxy.corp:3129", *bc.Spec.Source.Git.HTTPSProxy)
+		require.Equal(t, ".corp,localhost", *bc.Spec.Source.Git.NoProxy)
+	})
+
+	t.Run("with context dir and proxies", func(t *testing.T) {
+		bc := newBuildConfig(cp, builderIS, newGitSource(devconsoleapi.GitSourceSpec{
+			ContextDir: "services/frontend",
+			HttpsProxy: "https://proxy.corp:3129",
+			NoProxy:    ".corp",
+		}), nil)
+		require.Equal(t, "services/frontend", bc.Spec.Source.ContextDir)
+		require.Nil(t, bc.Spec.Source.Git.HTTPProxy, "build config should not have any http proxy")
+		require.Equal(t, "https://proxy.corp:3129", *bc.Spec.Source.Git.HTTPSProxy)
+		require.Equal(t, ".corp", *bc.Spec.Source.Git.NoProxy)
+	})
+
+	t.Run("with every combination of context dir and proxies", func(t *testing.T) {
+		for i := 0; i < 16; i++ {
+			spec := devconsoleapi.GitSourceSpec{}
+			if i&1 != 0 {
+				spec.ContextDir = "services/frontend"
+			}
+			if i&2 != 0 {
+				spec.HttpProxy = "http://proxy.corp:3128"
+			}
+			if i&4 != 0 {
+				spec.HttpsProxy = "https://proxy.corp:3129"
+			}
+			if i&8 != 0 {
+				spec.NoProxy = ".corp,localhost"
+			}
+			t.Run(fmt.Sprintf("contextDir=%q httpProxy=%q httpsProxy=%q noProxy=%q", spec.ContextDir, spec.HttpProxy, spec.HttpsProxy, spec.NoProxy), func(t *testing.T) {
+				bc := newBuildConfig(cp, builderIS, newGitSource(spec), nil)
+				require.Equal(t, spec.ContextDir, bc.Spec.Source.ContextDir)
+				for _, proxy := range []struct {
+					desired string
+					actual  *string
+				}{
+					{spec.HttpProxy, bc.Spec.Source.Git.HTTPProxy},
+					{spec.HttpsProxy, bc.Spec.Source.Git.HTTPSProxy},
+					{spec.NoProxy, bc.Spec.Source.Git.NoProxy},
+				} {
+					if proxy.desired == "" {
+						require.Nil(t, proxy.actual, "build config should not have an unset proxy")
+						continue
+					}
+					require.NotNil(t, proxy.actual)
+					require.Equal(t, proxy.desired, *proxy.actual)
+				}
+			})
+		}
+	})
+
+	t.Run("with docker build strategy", func(t *testing.T) {
+		cpDocker := cp.DeepCopy()
+		cpDocker.Spec.BuildStrategy = BuildStrategyDocker
+		cpDocker.Spec.DockerfilePath = "docker/Dockerfile.prod"
+		cpDocker.Spec.BuildArgs = []corev1.EnvVar{{Name: "NODE_ENV", Value: "production"}}
+		bc := newBuildConfig(cpDocker, nil, newGitSource(devconsoleapi.GitSourceSpec{}), nil)
+		require.Nil(t, bc.Spec.Strategy.SourceStrategy, "build config should not use S2I")
+		require.NotNil(t, bc.Spec.Strategy.DockerStrategy)
+		require.Equal(t, buildv1.DockerBuildStrategyType, bc.Spec.Strategy.Type)
+		require.Equal(t, "docker/Dockerfile.prod", bc.Spec.Strategy.DockerStrategy.DockerfilePath)
+		require.Equal(t, cpDocker.Spec.BuildArgs, bc.Spec.Strategy.DockerStrategy.BuildArgs)
+		require.Equal(t, 2, len(bc.Spec.Triggers), "build config should not be triggered on image change")
+		require.Equal(t, buildv1.ConfigChangeBuildTriggerType, bc.Spec.Triggers[0].Type)
+		require.Equal(t, buildv1.GenericWebHookBuildTriggerType, bc.Spec.Triggers[1].Type)
+	})
+
+	t.Run("with webhook of the git source flavor", func(t *testing.T) {
+		bc := newBuildConfig(cp, builderIS, newGitSource(devconsoleapi.GitSourceSpec{Flavor: "GitLab"}), nil)
+		require.Equal(t, buildv1.GitLabWebHookBuildTriggerType, bc.Spec.Triggers[1].Type)
+		require.Equal(t, Name+"-webhook", bc.Spec.Triggers[1].GitLabWebHook.SecretReference.Name)
+	})
+
+	t.Run("with webhook of the git source host", func(t *testing.T) {
+		gitSource := newGitSource(devconsoleapi.GitSourceSpec{})
+		gitSource.Spec.URL = "https://github.com/redhat-developer/devconsole-operator"
+		bc := newBuildConfig(cp, builderIS, gitSource, nil)
+		require.Equal(t, buildv1.GitHubWebHookBuildTriggerType, bc.Spec.Triggers[1].Type)
+		require.Equal(t, Name+"-webhook", bc.Spec.Triggers[1].GitHubWebHook.SecretReference.Name)
+	})
+}
+
+func TestNewKnativeService(t *testing.T) {
+	cp := &devconsoleapi.Component{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name,
+			Namespace: Namespace,
+		},
+		Spec: devconsoleapi.ComponentSpec{
+			BuildType:      "nodejs",
+			GitSourceRef:   "my-git-source",
+			DeploymentKind: DeploymentKindKnativeService,
+			Port:           Port,
+		},
+	}
+	image := "image-registry.openshift-image-registry.svc:5000/test-project/mycomp@sha256:0123"
+	ports := []corev1.ContainerPort{{ContainerPort: Port, Protocol: corev1.ProtocolTCP}}
+
+	t.Run("with image and port", func(t *testing.T) {
+		ksvc := newKnativeServiceFor(cp, image, ports, nil, "")
+		require.Equal(t, knativeServiceGVK, ksvc.GroupVersionKind())
+		require.Equal(t, Name, ksvc.GetName())
+		container := knativeContainer(ksvc)
+		require.NotNil(t, container)
+		require.Equal(t, image, container["image"])
+		require.Equal(t, []interface{}{map[string]interface{}{"containerPort": int64(Port)}}, container["ports"])
+	})
+
+	t.Run("update only the owned fields", func(t *testing.T) {
+		found := newKnativeServiceFor(cp, image, ports, nil, "")
+		// fields defaulted by Knative are kept
+		knativeContainer(found)["readinessProbe"] = map[string]interface{}{"successThreshold": int64(1)}
+		require.False(t, updateKnativeService(found, newKnativeServiceFor(cp, image, ports, nil, "")), "knative service should be up to date")
+
+		newImage := "image-registry.openshift-image-registry.svc:5000/test-project/mycomp@sha256:4567"
+		require.True(t, updateKnativeService(found, newKnativeServiceFor(cp, newImage, ports, nil, "")), "knative service image should be updated")
+		require.Equal(t, newImage, knativeContainer(found)["image"])
+		require.NotNil(t, knativeContainer(found)["readinessProbe"])
+	})
+
+	t.Run("ready condition without route", func(t *testing.T) {
+		exposed := cp.DeepCopy()
+		exposed.Spec.Exposed = true
+		require.NotContains(t, readyConditionTypes(exposed), ConditionRouteAdmitted)
+		require.Contains(t, readyConditionTypes(exposed), ConditionDeploymentAvailable)
+	})
+}
+
+func TestNewServiceAndRouteWithMultiplePorts(t *testing.T) {
+	cp := &devconsoleapi.Component{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name,
+			Namespace: Namespace,
+		},
+		Spec: devconsoleapi.ComponentSpec{
+			BuildType:    "nodejs",
+			GitSourceRef: "my-git-source",
+			Exposed:      true,
+		},
+	}
+	detected := []corev1.ContainerPort{
+		{ContainerPort: 9090, Protocol: corev1.ProtocolUDP},
+		{ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+		{ContainerPort: 8080},
+		{ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+	}
+
+	t.Run("all ports named and sorted", func(t *testing.T) {
+		ports := namedPorts(detected)
+		require.Equal(t, []corev1.ContainerPort{
+			{Name: "8080-tcp", ContainerPort: 8080, Protocol: corev1.ProtocolTCP},
+			{Name: "8443-tcp", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+			{Name: "9090-udp", ContainerPort: 9090, Protocol: corev1.ProtocolUDP},
+		}, ports)
+	})
+
+	t.Run("service exposes every port", func(t *testing.T) {
+		svc, err := newService(cp, detected)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(svc.Spec.Ports))
+		require.Equal(t, "9090-udp", svc.Spec.Ports[2].Name)
+		require.Equal(t, corev1.ProtocolUDP, svc.Spec.Ports[2].Protocol)
+		require.Equal(t, intstr.FromInt(9090), svc.Spec.Ports[2].TargetPort)
+	})
+
+	t.Run("route targets the lowest tcp port", func(t *testing.T) {
+		rte := newRoute(cp, primaryPort(cp, namedPorts(detected)), nil)
+		require.Equal(t, intstr.FromString("8080-tcp"), rte.Spec.Port.TargetPort)
+	})
+
+	t.Run("route targets the port of the spec", func(t *testing.T) {
+		cpWithPort := cp.DeepCopy()
+		cpWithPort.Spec.Port = 8443
+		rte := newRoute(cpWithPort, primaryPort(cpWithPort, namedPorts(detected)), nil)
+		require.Equal(t, intstr.FromString("8443-tcp"), rte.Spec.Port.TargetPort)
+	})
+
+	t.Run("no route for udp only ports", func(t *testing.T) {
+		require.Nil(t, primaryPort(cp, namedPorts([]corev1.ContainerPort{{ContainerPort: 9090, Protocol: corev1.ProtocolUDP}})))
+	})
 }
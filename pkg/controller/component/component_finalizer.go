@@ -0,0 +1,199 @@
+package component
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/openshift/api/apps/v1"
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// componentFinalizer is set on each Component so that the operator can remove the resources created for it before
+// the Component is deleted.
+const componentFinalizer = "devconsole.openshift.io/component"
+
+// AddFinalizer adds the devconsole finalizer to the Component if not already present.
+func (r *ReconcileComponent) AddFinalizer(cp *devconsoleapi.Component) error {
+	if hasFinalizer(cp) {
+		return nil
+	}
+	log.Info("** Adding finalizer to Component", "Component.Namespace", cp.Namespace, "Component.Name", cp.Name)
+	cp.Finalizers = append(cp.Finalizers, componentFinalizer)
+	if err := r.client.Update(context.TODO(), cp); err != nil {
+		log.Error(err, "** failed to add finalizer to component **")
+		return err
+	}
+	return nil
+}
+
+// Finalize removes every resource labelled for the Component being deleted, in any namespace, then removes the
+// devconsole finalizer so that the Component can be deleted.
+func (r *ReconcileComponent) Finalize(cp *devconsoleapi.Component) error {
+	if !hasFinalizer(cp) {
+		return nil
+	}
+	selector := labels.SelectorFromSet(map[string]string{componentUIDLabel: string(cp.UID)})
+	lists := []runtime.Object{
+		&buildv1.BuildList{},
+		&buildv1.BuildConfigList{},
+		&v1.DeploymentConfigList{},
+		&appsv1.DeploymentList{},
+		&autoscalingv2beta1.HorizontalPodAutoscalerList{},
+		&corev1.ServiceList{},
+		&corev1.PersistentVolumeClaimList{},
+		&corev1.SecretList{},
+		&routev1.RouteList{},
+	}
+	for _, list := range lists {
+		if err := r.deleteAll(list, &client.ListOptions{LabelSelector: selector}); err != nil {
+			return err
+		}
+	}
+	// builds copy the labels of their BuildConfig at creation time, the ones started before the Component UID label
+	// was set are found by the name of their BuildConfig
+	buildSelector := labels.SelectorFromSet(map[string]string{buildv1.BuildConfigLabel: cp.Name})
+	if err := r.deleteAll(&buildv1.BuildList{}, &client.ListOptions{Namespace: cp.Namespace, LabelSelector: buildSelector}); err != nil {
+		return err
+	}
+	// Knative Services are only found on clusters with Knative Serving
+	if err := r.deleteAll(newKnativeServiceList(), &client.ListOptions{LabelSelector: selector}); err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+	if err := r.deleteImageStreams(cp, selector); err != nil {
+		return err
+	}
+
+	log.Info("** Removing finalizer from Component", "Component.Namespace", cp.Namespace, "Component.Name", cp.Name)
+	var finalizers []string
+	for _, finalizer := range cp.Finalizers {
+		if finalizer != componentFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	cp.Finalizers = finalizers
+	if err := r.client.Update(context.TODO(), cp); err != nil {
+		log.Error(err, "** failed to remove finalizer from component **")
+		return err
+	}
+	return nil
+}
+
+// deleteAll deletes all the objects of the list matching the given options. Dependent objects, like the pods of
+// builds and deployments, are removed by the garbage collector.
+func (r *ReconcileComponent) deleteAll(list runtime.Object, opts *client.ListOptions) error {
+	if err := r.client.List(context.TODO(), opts, list); err != nil {
+		log.Error(err, "** failed to list resources to delete **")
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := r.delete(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteImageStreams deletes the image streams created for the Component. A builder image stream shared with other
+// Components is kept, only the owner reference to the deleted Component is removed.
+func (r *ReconcileComponent) deleteImageStreams(cp *devconsoleapi.Component, selector labels.Selector) error {
+	isList := &imagev1.ImageStreamList{}
+	if err := r.client.List(context.TODO(), &client.ListOptions{LabelSelector: selector}, isList); err != nil {
+		log.Error(err, "** failed to list resources to delete **")
+		return err
+	}
+	// builder image streams created by other components are owned but not labelled for this one, there is none when
+	// the build type has not been detected yet
+	if buildType := builderName(cp); buildType != "" {
+		builderIS := &imagev1.ImageStream{}
+		err := r.client.Get(context.TODO(), client.ObjectKey{Namespace: cp.Namespace, Name: buildType}, builderIS)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && builderIS.Labels[componentUIDLabel] != string(cp.UID) && isOwnedBy(builderIS, cp) {
+			isList.Items = append(isList.Items, *builderIS)
+		}
+	}
+	for i := range isList.Items {
+		is := &isList.Items[i]
+		if !hasOtherOwners(is, cp) {
+			if err := r.delete(is); err != nil {
+				return err
+			}
+			continue
+		}
+		var refs []metav1.OwnerReference
+		for _, ref := range is.OwnerReferences {
+			if ref.UID != cp.UID {
+				refs = append(refs, ref)
+			}
+		}
+		is.OwnerReferences = refs
+		log.Info("** Removing owner reference from shared ImageStream", "ImageStream.Namespace", is.Namespace, "ImageStream.Name", is.Name)
+		if err := r.client.Update(context.TODO(), is); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "** ImageStream update fails **")
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileComponent) delete(obj runtime.Object) error {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("👻👻 Deleting %T 👻👻", obj), "Namespace", accessor.GetNamespace(), "Name", accessor.GetName())
+	err = r.client.Delete(context.TODO(), obj, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if err != nil && !errors.IsNotFound(err) {
+		log.Error(err, "** resource deletion fails **")
+		return err
+	}
+	return nil
+}
+
+func hasFinalizer(cp *devconsoleapi.Component) bool {
+	for _, finalizer := range cp.Finalizers {
+		if finalizer == componentFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+func isOwnedBy(object metav1.Object, cp *devconsoleapi.Component) bool {
+	for _, ref := range object.GetOwnerReferences() {
+		if ref.UID == cp.UID {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOtherOwners(object metav1.Object, cp *devconsoleapi.Component) bool {
+	for _, ref := range object.GetOwnerReferences() {
+		if ref.UID != cp.UID {
+			return true
+		}
+	}
+	return false
+}
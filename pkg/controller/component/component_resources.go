@@ -12,21 +12,50 @@ import (
 
 	"github.com/redhat-developer/devconsole-operator/pkg/resource"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
-func newImageStreamFromDocker(cp *devconsoleapi.Component) *imagev1.ImageStream {
+// componentUIDLabel is set on every resource created for a Component, its value is the UID of the Component. It
+// allows the finalizer to find all of them, including the ones that owner references cannot cover.
+const componentUIDLabel = "devconsole.openshift.io/component-uid"
+
+// labelsForComponent returns the labels of the resources created for the given Component.
+func labelsForComponent(cp *devconsoleapi.Component) map[string]string {
 	labels := resource.GetLabelsForCR(cp)
+	labels[componentUIDLabel] = string(cp.UID)
+	return labels
+}
+
+// newImageStreamFromDocker returns the builder image stream importing the docker images of the builder. The latest
+// tag points to the builder's image, each version of the builder has its own tag.
+func newImageStreamFromDocker(cp *devconsoleapi.Component, builder *BuilderImage) *imagev1.ImageStream {
+	labels := labelsForComponent(cp)
 	annotations := resource.GetAnnotationsForCR(cp)
 
-	if _, ok := buildTypeImages[cp.Spec.BuildType]; !ok {
-		return nil
+	tags := []imagev1.TagReference{
+		{
+			Name: "latest",
+			From: &corev1.ObjectReference{
+				Kind: "DockerImage",
+				Name: builder.Image,
+			},
+		},
+	}
+	for _, version := range builder.sortedVersions() {
+		tags = append(tags, imagev1.TagReference{
+			Name: version,
+			From: &corev1.ObjectReference{
+				Kind: "DockerImage",
+				Name: builder.Versions[version],
+			},
+		})
 	}
 	return &imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{
-		Name:        cp.Spec.BuildType,
+		Name:        builderName(cp),
 		Namespace:   cp.Namespace,
 		Labels:      labels,
 		Annotations: annotations,
@@ -34,45 +63,69 @@ func newImageStreamFromDocker(cp *devconsoleapi.Component) *imagev1.ImageStream
 		LookupPolicy: imagev1.ImageLookupPolicy{
 			Local: false,
 		},
-		Tags: []imagev1.TagReference{
-			{
-				Name: "latest",
-				From: &corev1.ObjectReference{
-					Kind: "DockerImage",
-					Name: buildTypeImages[cp.Spec.BuildType],
-				},
-			},
+		Tags: tags,
+	}}
+}
+
+// newGitSourceAnalysis returns the GitSourceAnalysis detecting the build type of the Component's GitSource.
+func newGitSourceAnalysis(cp *devconsoleapi.Component) *devconsoleapi.GitSourceAnalysis {
+	return &devconsoleapi.GitSourceAnalysis{ObjectMeta: metav1.ObjectMeta{
+		Name:        cp.Name,
+		Namespace:   cp.Namespace,
+		Labels:      labelsForComponent(cp),
+		Annotations: resource.GetAnnotationsForCR(cp),
+	}, Spec: devconsoleapi.GitSourceAnalysisSpec{
+		GitSourceRef: devconsoleapi.GitSourceRef{
+			Name: cp.Spec.GitSourceRef,
 		},
 	}}
 }
 
+// newOutputImageStream returns the image stream holding the images deployed for the Component. For an image-only
+// Component, its latest tag imports the external image on a schedule.
 func newOutputImageStream(cp *devconsoleapi.Component) *imagev1.ImageStream {
-	labels := resource.GetLabelsForCR(cp)
+	labels := labelsForComponent(cp)
 	annotations := resource.GetAnnotationsForCR(cp)
-	return &imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{
+	is := &imagev1.ImageStream{ObjectMeta: metav1.ObjectMeta{
 		Name:        cp.Name,
 		Namespace:   cp.Namespace,
 		Labels:      labels,
 		Annotations: annotations,
 	}}
+	if isImageComponent(cp) {
+		is.Spec.Tags = []imagev1.TagReference{
+			{
+				Name: "latest",
+				From: &corev1.ObjectReference{
+					Kind: "DockerImage",
+					Name: cp.Spec.Image,
+				},
+				ImportPolicy: imagev1.TagImportPolicy{
+					Scheduled: true,
+				},
+			},
+		}
+	}
+	return is
 }
 
 func newBuildConfig(cp *devconsoleapi.Component, builder *imagev1.ImageStream, gitSource *devconsoleapi.GitSource, secret *corev1.Secret) *buildv1.BuildConfig {
-	labels := resource.GetLabelsForCR(cp)
+	labels := labelsForComponent(cp)
 	annotations := resource.GetAnnotationsForCR(cp)
 	buildSource := buildv1.BuildSource{
 		Git: &buildv1.GitBuildSource{
-			URI: gitSource.Spec.URL,
-			Ref: gitSource.Spec.Ref,
+			URI:         gitSource.Spec.URL,
+			Ref:         gitSource.Spec.Ref,
+			ProxyConfig: newProxyConfig(gitSource),
 		},
-		Type: buildv1.BuildSourceGit,
+		ContextDir: gitSource.Spec.ContextDir,
+		Type:       buildv1.BuildSourceGit,
 	}
 	if secret != nil {
 		buildSource.SourceSecret = &corev1.LocalObjectReference{
 			Name: secret.Name,
 		}
 	}
-	incremental := true
 	return &buildv1.BuildConfig{
 		ObjectMeta: metav1.ObjectMeta{Name: cp.Name, Namespace: cp.Namespace, Labels: labels, Annotations: annotations},
 		Spec: buildv1.BuildConfigSpec{
@@ -83,39 +136,118 @@ func newBuildConfig(cp *devconsoleapi.Component, builder *imagev1.ImageStream, g
 						Name: cp.Name + ":latest",
 					},
 				},
-				Source: buildSource,
-				Strategy: buildv1.BuildStrategy{
-					SourceStrategy: &buildv1.SourceBuildStrategy{
-						From: corev1.ObjectReference{
-							Kind:      "ImageStreamTag",
-							Name:      builder.Name + ":latest",
-							Namespace: builder.Namespace,
-						},
-						Incremental: &incremental,
-					},
-				},
+				Source:   buildSource,
+				Strategy: newBuildStrategy(cp, builder),
+			},
+			Triggers: newBuildTriggers(cp, gitSource),
+		},
+	}
+}
+
+// newProxyConfig returns the proxy settings used to clone the GitSource, only the ones which are set are provided.
+func newProxyConfig(gitSource *devconsoleapi.GitSource) buildv1.ProxyConfig {
+	proxyConfig := buildv1.ProxyConfig{}
+	if httpProxy := gitSource.Spec.HttpProxy; httpProxy != "" {
+		proxyConfig.HTTPProxy = &httpProxy
+	}
+	if httpsProxy := gitSource.Spec.HttpsProxy; httpsProxy != "" {
+		proxyConfig.HTTPSProxy = &httpsProxy
+	}
+	if noProxy := gitSource.Spec.NoProxy; noProxy != "" {
+		proxyConfig.NoProxy = &noProxy
+	}
+	return proxyConfig
+}
+
+// newDeployment returns the Kubernetes Deployment of the Component, the alternative to its DeploymentConfig. The image
+// of its container is resolved from the output image stream by the image trigger set in its annotations.
+func newDeployment(cp *devconsoleapi.Component, output *imagev1.ImageStream, containerPorts []corev1.ContainerPort, builder *BuilderImage, configHash string) *appsv1.Deployment {
+	labels := labelsForComponent(cp)
+	podLabels := resource.GetLabelsForCR(cp)
+	annotations := newPodAnnotations(cp, configHash)
+	deploymentAnnotations := resource.GetAnnotationsForCR(cp)
+	deploymentAnnotations[imageTriggersAnnotation] = newImageTriggers(output.Name+":latest", output.Name, false)
+	containerPorts = namedPorts(containerPorts)
+	replicas := replicasOf(cp)
+	strategy, progressDeadlineSeconds := newDeploymentStrategy(cp)
+	volumes, _ := newPodVolumes(cp)
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cp.Name,
+			Namespace:   cp.Namespace,
+			Labels:      labels,
+			Annotations: deploymentAnnotations,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Strategy:                strategy,
+			ProgressDeadlineSeconds: &progressDeadlineSeconds,
+			Paused:                  isRolloutPaused(cp),
+			Replicas:                &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels,
 			},
-			Triggers: []buildv1.BuildTriggerPolicy{
-				{
-					Type: "ConfigChange",
-				}, {
-					Type:        "ImageChange",
-					ImageChange: &buildv1.ImageChangeTrigger{},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      podLabels,
+					Annotations: annotations,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						newContainer(cp, output.Name, containerPorts, builder),
+					},
+					Volumes: volumes,
 				},
 			},
 		},
 	}
 }
 
-func newDeploymentConfig(cp *devconsoleapi.Component, output *imagev1.ImageStream, containerPorts []corev1.ContainerPort) *v1.DeploymentConfig {
-	labels := resource.GetLabelsForCR(cp)
+// newPodAnnotations returns the annotations of the pods of the Component, with the hash of the configuration of their
+// environment, the last redeploy requested and the names of the volumes and environment variables set by the operator,
+// if any.
+func newPodAnnotations(cp *devconsoleapi.Component, configHash string) map[string]string {
 	annotations := resource.GetAnnotationsForCR(cp)
-	if containerPorts == nil {
-		containerPorts = []corev1.ContainerPort{{
-			ContainerPort: 8080,
-			Protocol:      corev1.ProtocolTCP,
-		}}
+	if configHash != "" {
+		annotations[configHashAnnotation] = configHash
+	}
+	var volumeNames, envNames []string
+	for _, volume := range cp.Spec.Volumes {
+		volumeNames = append(volumeNames, volume.Name)
 	}
+	for _, env := range cp.Spec.Env {
+		envNames = append(envNames, env.Name)
+	}
+	if len(volumeNames) > 0 {
+		annotations[ownedVolumesAnnotation] = joinOwnedNames(volumeNames)
+	}
+	if len(envNames) > 0 {
+		annotations[ownedEnvAnnotation] = joinOwnedNames(envNames)
+	}
+	if redeploy := cp.Annotations[redeployAnnotation]; redeploy != "" {
+		annotations[redeployAnnotation] = redeploy
+	}
+	return annotations
+}
+
+// newImageTriggers returns the value of the image trigger annotation updating the image of the container from the
+// image stream tag, unless it is paused.
+func newImageTriggers(imageStreamTag, containerName string, paused bool) string {
+	if paused {
+		return fmt.Sprintf(`[{"from":{"kind":"ImageStreamTag","name":"%s"},"fieldPath":"spec.template.spec.containers[?(@.name==\"%s\")].image","paused":true}]`, imageStreamTag, containerName)
+	}
+	return fmt.Sprintf(`[{"from":{"kind":"ImageStreamTag","name":"%s"},"fieldPath":"spec.template.spec.containers[?(@.name==\"%s\")].image"}]`, imageStreamTag, containerName)
+}
+
+// newDeploymentConfig returns the DeploymentConfig of the Component, rolled out when the output image stream or the
+// configuration of its environment changes. The builder image provides the default resources of its container, its
+// volumes are mounted from the PersistentVolumeClaims of the Component.
+func newDeploymentConfig(cp *devconsoleapi.Component, output *imagev1.ImageStream, containerPorts []corev1.ContainerPort, builder *BuilderImage, configHash string) *v1.DeploymentConfig {
+	labels := labelsForComponent(cp)
+	podLabels := resource.GetLabelsForCR(cp)
+	annotations := resource.GetAnnotationsForCR(cp)
+	podAnnotations := newPodAnnotations(cp, configHash)
+	containerPorts = namedPorts(containerPorts)
+	volumes, _ := newPodVolumes(cp)
 	return &v1.DeploymentConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        cp.Name,
@@ -124,25 +256,22 @@ func newDeploymentConfig(cp *devconsoleapi.Component, output *imagev1.ImageStrea
 			Annotations: annotations,
 		},
 		Spec: v1.DeploymentConfigSpec{
-			Strategy: v1.DeploymentStrategy{
-				Type: v1.DeploymentStrategyTypeRecreate,
-			},
-			Replicas: 1,
-			Selector: labels,
+			Strategy: newDeploymentConfigStrategy(cp),
+			Paused:   isRolloutPaused(cp),
+			Replicas: replicasOf(cp),
+			Selector: podLabels,
 			Template: &corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        cp.Name,
 					Namespace:   cp.Namespace,
-					Labels:      labels,
-					Annotations: annotations,
+					Labels:      podLabels,
+					Annotations: podAnnotations,
 				},
 				Spec: corev1.PodSpec{
-					Containers: []corev1.Container{{
-						Name:  output.Name,
-						Image: output.Name + ":latest",
-						Ports: containerPorts,
-					},
+					Containers: []corev1.Container{
+						newContainer(cp, output.Name, containerPorts, builder),
 					},
+					Volumes: volumes,
 				},
 			},
 			Triggers: []v1.DeploymentTriggerPolicy{{
@@ -165,20 +294,23 @@ func newDeploymentConfig(cp *devconsoleapi.Component, output *imagev1.ImageStrea
 	}
 }
 
-func newService(cp *devconsoleapi.Component, port int32) (*corev1.Service, error) {
-	labels := resource.GetLabelsForCR(cp)
+// newService returns the Service of the Component, exposing all its container ports under the same names. The ports
+// detected from the image may be privileged ones, only the port of the spec is checked by validatePort.
+func newService(cp *devconsoleapi.Component, containerPorts []corev1.ContainerPort) (*corev1.Service, error) {
+	labels := labelsForComponent(cp)
 	annotations := resource.GetAnnotationsForCR(cp)
-	if port > 65536 || port < 1024 {
-		return nil, fmt.Errorf("port %d is out of range [1024-65535]", port)
-	}
 	var svcPorts []corev1.ServicePort
-	svcPort := corev1.ServicePort{
-		Name:       cp.Name + "-tcp",
-		Port:       port,
-		Protocol:   corev1.ProtocolTCP,
-		TargetPort: intstr.FromInt(int(port)),
+	for _, port := range namedPorts(containerPorts) {
+		if port.ContainerPort > 65535 || port.ContainerPort < 1 {
+			return nil, fmt.Errorf("port %d is out of range [1-65535]", port.ContainerPort)
+		}
+		svcPorts = append(svcPorts, corev1.ServicePort{
+			Name:       port.Name,
+			Port:       port.ContainerPort,
+			Protocol:   port.Protocol,
+			TargetPort: intstr.FromInt(int(port.ContainerPort)),
+		})
 	}
-	svcPorts = append(svcPorts, svcPort)
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        cp.Name,
@@ -188,16 +320,18 @@ func newService(cp *devconsoleapi.Component, port int32) (*corev1.Service, error
 		},
 		Spec: corev1.ServiceSpec{
 			Ports: svcPorts,
-			Selector: map[string]string{
-				"deploymentconfig": cp.Name,
-			},
+			// the labels of the pod template, selected by the DeploymentConfig or the Deployment too
+			Selector: resource.GetLabelsForCR(cp),
 		},
 	}
 	return svc, nil
 }
 
-func newRoute(cp *devconsoleapi.Component) *routev1.Route {
-	labels := resource.GetLabelsForCR(cp)
+// newRoute returns the Route of the Component on its host and path, targeting the Service port named after the given
+// primary port and terminating TLS with the given configuration, if any. The router generates the host when none is
+// set.
+func newRoute(cp *devconsoleapi.Component, port *corev1.ContainerPort, tls *routev1.TLSConfig) *routev1.Route {
+	labels := labelsForComponent(cp)
 	annotations := resource.GetAnnotationsForCR(cp)
 	route := &routev1.Route{
 		ObjectMeta: metav1.ObjectMeta{
@@ -207,13 +341,17 @@ func newRoute(cp *devconsoleapi.Component) *routev1.Route {
 			Annotations: annotations,
 		},
 		Spec: routev1.RouteSpec{
+			Host:           cp.Spec.Host,
+			Path:           cp.Spec.Path,
+			WildcardPolicy: routev1.WildcardPolicyType(cp.Spec.WildcardPolicy),
 			To: routev1.RouteTargetReference{
 				Kind: "Service",
 				Name: cp.Name,
 			},
 			Port: &routev1.RoutePort{
-				TargetPort: intstr.IntOrString{IntVal: cp.Spec.Port, StrVal: fmt.Sprintf("%d-tcp", cp.Spec.Port)},
+				TargetPort: intstr.FromString(portName(port.ContainerPort, port.Protocol)),
 			},
+			TLS: tls,
 		},
 	}
 	return route
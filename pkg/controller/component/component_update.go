@@ -0,0 +1,547 @@
+package component
+
+import (
+	"strings"
+
+	v1 "github.com/openshift/api/apps/v1"
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// The update* functions below copy the fields owned by the operator from the desired object (as generated in
+// component_resources.go) into the live one. Fields which are not set by the operator, or which are defaulted or
+// managed by other controllers (cluster IP, route host, container image set by image triggers...) are left untouched.
+// They return true when the live object has been modified and needs to be updated.
+
+// Annotations of the pod template recording the names of the volumes and of the environment variables set by the
+// operator, so that the ones removed from the Component are removed from the live pod template while the ones added by
+// other controllers are kept.
+const (
+	ownedVolumesAnnotation = "devconsole.openshift.io/owned-volumes"
+	ownedEnvAnnotation     = "devconsole.openshift.io/owned-env"
+)
+
+// updateObjectMeta adds the labels and annotations of the desired object to the live one without removing the
+// labels and annotations set by others.
+func updateObjectMeta(found, desired *metav1.ObjectMeta) bool {
+	updated := false
+	if found.Labels == nil && len(desired.Labels) > 0 {
+		found.Labels = map[string]string{}
+	}
+	for k, v := range desired.Labels {
+		if found.Labels[k] != v {
+			found.Labels[k] = v
+			updated = true
+		}
+	}
+	if found.Annotations == nil && len(desired.Annotations) > 0 {
+		found.Annotations = map[string]string{}
+	}
+	for k, v := range desired.Annotations {
+		if found.Annotations[k] != v {
+			found.Annotations[k] = v
+			updated = true
+		}
+	}
+	return updated
+}
+
+func updateBuildConfig(found, desired *buildv1.BuildConfig) bool {
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	if !equality.Semantic.DeepEqual(found.Spec.Source, desired.Spec.Source) {
+		found.Spec.Source = desired.Spec.Source
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.Spec.Strategy, desired.Spec.Strategy) {
+		found.Spec.Strategy = desired.Spec.Strategy
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.Spec.Output, desired.Spec.Output) {
+		found.Spec.Output = desired.Spec.Output
+		updated = true
+	}
+	if !buildTriggersEqual(found.Spec.Triggers, desired.Spec.Triggers) {
+		found.Spec.Triggers = desired.Spec.Triggers
+		updated = true
+	}
+	return updated
+}
+
+// buildTriggersEqual compares the build triggers ignoring the fields filled in by the build controller.
+func buildTriggersEqual(found, desired []buildv1.BuildTriggerPolicy) bool {
+	if len(found) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		f, d := found[i], desired[i]
+		if f.Type != d.Type {
+			return false
+		}
+		if f.ImageChange != nil && d.ImageChange != nil {
+			if !equality.Semantic.DeepEqual(f.ImageChange.From, d.ImageChange.From) {
+				return false
+			}
+		} else if (f.ImageChange == nil) != (d.ImageChange == nil) {
+			return false
+		}
+		if !equality.Semantic.DeepEqual(f.GitHubWebHook, d.GitHubWebHook) ||
+			!equality.Semantic.DeepEqual(f.GenericWebHook, d.GenericWebHook) ||
+			!equality.Semantic.DeepEqual(f.GitLabWebHook, d.GitLabWebHook) ||
+			!equality.Semantic.DeepEqual(f.BitbucketWebHook, d.BitbucketWebHook) {
+			return false
+		}
+	}
+	return true
+}
+
+// updateImageStreamTags updates the tags of the image stream with the desired ones, the tags added by others are kept.
+func updateImageStreamTags(found, desired *imagev1.ImageStream) bool {
+	updated := false
+	for _, desiredTag := range desired.Spec.Tags {
+		var foundTag *imagev1.TagReference
+		for i := range found.Spec.Tags {
+			if found.Spec.Tags[i].Name == desiredTag.Name {
+				foundTag = &found.Spec.Tags[i]
+			}
+		}
+		if foundTag == nil {
+			found.Spec.Tags = append(found.Spec.Tags, desiredTag)
+			updated = true
+			continue
+		}
+		if foundTag.From == nil || desiredTag.From == nil || foundTag.From.Kind != desiredTag.From.Kind || foundTag.From.Name != desiredTag.From.Name {
+			foundTag.From = desiredTag.From
+			updated = true
+		}
+		if foundTag.ImportPolicy != desiredTag.ImportPolicy {
+			foundTag.ImportPolicy = desiredTag.ImportPolicy
+			updated = true
+		}
+	}
+	return updated
+}
+
+func updateDeploymentConfig(found, desired *v1.DeploymentConfig) bool {
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	if !deploymentConfigStrategyEqual(found.Spec.Strategy, desired.Spec.Strategy) {
+		found.Spec.Strategy = desired.Spec.Strategy
+		updated = true
+	}
+	if found.Spec.Paused != desired.Spec.Paused {
+		found.Spec.Paused = desired.Spec.Paused
+		updated = true
+	}
+	if found.Spec.Replicas != desired.Spec.Replicas {
+		found.Spec.Replicas = desired.Spec.Replicas
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.Spec.Selector, desired.Spec.Selector) {
+		found.Spec.Selector = desired.Spec.Selector
+		updated = true
+	}
+	if !deploymentTriggersEqual(found.Spec.Triggers, desired.Spec.Triggers) {
+		found.Spec.Triggers = desired.Spec.Triggers
+		updated = true
+	}
+	if found.Spec.Template == nil {
+		found.Spec.Template = desired.Spec.Template
+		return true
+	}
+	if updatePodTemplateSpec(found.Spec.Template, desired.Spec.Template) {
+		updated = true
+	}
+	return updated
+}
+
+// updateHorizontalPodAutoscaler updates the target, the bounds and the metrics of the autoscaler.
+func updateHorizontalPodAutoscaler(found, desired *autoscalingv2beta1.HorizontalPodAutoscaler) bool {
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	if !equality.Semantic.DeepEqual(found.Spec, desired.Spec) {
+		found.Spec = desired.Spec
+		updated = true
+	}
+	return updated
+}
+
+// deploymentConfigStrategyEqual compares the type and the parameters of the strategies, ignoring the fields defaulted
+// by the API server which the Component does not set, like the active deadline.
+func deploymentConfigStrategyEqual(found, desired v1.DeploymentStrategy) bool {
+	return found.Type == desired.Type &&
+		equality.Semantic.DeepEqual(found.RollingParams, desired.RollingParams) &&
+		equality.Semantic.DeepEqual(found.RecreateParams, desired.RecreateParams)
+}
+
+// updateDeployment updates the Deployment like the DeploymentConfig, except for its selector which is immutable.
+func updateDeployment(found, desired *appsv1.Deployment) bool {
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	if !equality.Semantic.DeepEqual(found.Spec.Strategy, desired.Spec.Strategy) {
+		found.Spec.Strategy = desired.Spec.Strategy
+		updated = true
+	}
+	if found.Spec.ProgressDeadlineSeconds == nil || *found.Spec.ProgressDeadlineSeconds != *desired.Spec.ProgressDeadlineSeconds {
+		found.Spec.ProgressDeadlineSeconds = desired.Spec.ProgressDeadlineSeconds
+		updated = true
+	}
+	if found.Spec.Paused != desired.Spec.Paused {
+		found.Spec.Paused = desired.Spec.Paused
+		updated = true
+	}
+	if found.Spec.Replicas == nil || *found.Spec.Replicas != *desired.Spec.Replicas {
+		found.Spec.Replicas = desired.Spec.Replicas
+		updated = true
+	}
+	if updatePodTemplateSpec(&found.Spec.Template, &desired.Spec.Template) {
+		updated = true
+	}
+	return updated
+}
+
+// deploymentTriggersEqual compares the deployment triggers ignoring the fields filled in by the deployment
+// controller, like the last triggered image.
+func deploymentTriggersEqual(found, desired []v1.DeploymentTriggerPolicy) bool {
+	if len(found) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		f, d := found[i], desired[i]
+		if f.Type != d.Type {
+			return false
+		}
+		if f.ImageChangeParams == nil || d.ImageChangeParams == nil {
+			if (f.ImageChangeParams == nil) != (d.ImageChangeParams == nil) {
+				return false
+			}
+			continue
+		}
+		if f.ImageChangeParams.Automatic != d.ImageChangeParams.Automatic ||
+			!equality.Semantic.DeepEqual(f.ImageChangeParams.ContainerNames, d.ImageChangeParams.ContainerNames) ||
+			f.ImageChangeParams.From.Kind != d.ImageChangeParams.From.Kind ||
+			f.ImageChangeParams.From.Name != d.ImageChangeParams.From.Name {
+			return false
+		}
+	}
+	return true
+}
+
+// updatePodTemplateSpec updates the volumes and the containers generated by the operator. Containers, volumes, volume
+// mounts and environment variables added by others (sidecars injected by admission controllers for instance) are kept
+// as is.
+func updatePodTemplateSpec(found, desired *corev1.PodTemplateSpec) bool {
+	ownedVolumes := ownedNames(found.Annotations[ownedVolumesAnnotation])
+	ownedEnv := ownedNames(found.Annotations[ownedEnvAnnotation])
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	for _, annotation := range []string{ownedVolumesAnnotation, ownedEnvAnnotation} {
+		if _, ok := desired.Annotations[annotation]; !ok && found.Annotations[annotation] != "" {
+			delete(found.Annotations, annotation)
+			updated = true
+		}
+	}
+	if mergeVolumes(&found.Spec.Volumes, desired.Spec.Volumes, ownedVolumes) {
+		updated = true
+	}
+	for _, desiredContainer := range desired.Spec.Containers {
+		container := findContainer(found.Spec.Containers, desiredContainer.Name)
+		if container == nil {
+			found.Spec.Containers = append(found.Spec.Containers, desiredContainer)
+			updated = true
+			continue
+		}
+		if updateContainer(container, &desiredContainer, ownedVolumes, ownedEnv) {
+			updated = true
+		}
+	}
+	return updated
+}
+
+// ownedNames returns the names recorded by one of the owned annotations of the pod template.
+func ownedNames(annotation string) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(annotation, ",") {
+		if name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// joinOwnedNames returns the value of an owned annotation recording the given names.
+func joinOwnedNames(names []string) string {
+	return strings.Join(names, ",")
+}
+
+// mergedItem designates an item of a merged list, taken from the desired or from the found list.
+type mergedItem struct {
+	desired bool
+	index   int
+}
+
+// mergeByName merges the desired items into the found ones, by name: the found items keep their order, the ones with a
+// desired name are replaced by the desired item, the ones owned by the operator which are not desired anymore are
+// removed and the new desired items are appended. It only works on the names of the items so that the volumes, the
+// volume mounts and the environment variables are merged the same way.
+func mergeByName(found, desired []string, owned map[string]bool) []mergedItem {
+	desiredIndexes := make(map[string]int)
+	for i, name := range desired {
+		desiredIndexes[name] = i
+	}
+	merged := make([]mergedItem, 0, len(found)+len(desired))
+	replaced := make(map[string]bool)
+	for i, name := range found {
+		j, isDesired := desiredIndexes[name]
+		switch {
+		case isDesired && !replaced[name]:
+			replaced[name] = true
+			merged = append(merged, mergedItem{desired: true, index: j})
+		case owned[name] && !isDesired:
+		default:
+			merged = append(merged, mergedItem{index: i})
+		}
+	}
+	for i, name := range desired {
+		if !replaced[name] {
+			replaced[name] = true
+			merged = append(merged, mergedItem{desired: true, index: i})
+		}
+	}
+	return merged
+}
+
+// mergeVolumes adds or updates the desired volumes, by name, and removes the volumes owned by the operator which are not
+// desired anymore.
+func mergeVolumes(found *[]corev1.Volume, desired []corev1.Volume, owned map[string]bool) bool {
+	var foundNames, desiredNames []string
+	for _, volume := range *found {
+		foundNames = append(foundNames, volume.Name)
+	}
+	for _, volume := range desired {
+		desiredNames = append(desiredNames, volume.Name)
+	}
+	var volumes []corev1.Volume
+	for _, item := range mergeByName(foundNames, desiredNames, owned) {
+		if item.desired {
+			volumes = append(volumes, desired[item.index])
+		} else {
+			volumes = append(volumes, (*found)[item.index])
+		}
+	}
+	if equality.Semantic.DeepEqual(*found, volumes) {
+		return false
+	}
+	*found = volumes
+	return true
+}
+
+// mergeVolumeMounts adds or updates the desired volume mounts, by volume name, and removes the mounts of the volumes
+// owned by the operator which are not desired anymore.
+func mergeVolumeMounts(found *[]corev1.VolumeMount, desired []corev1.VolumeMount, owned map[string]bool) bool {
+	var foundNames, desiredNames []string
+	for _, mount := range *found {
+		foundNames = append(foundNames, mount.Name)
+	}
+	for _, mount := range desired {
+		desiredNames = append(desiredNames, mount.Name)
+	}
+	var mounts []corev1.VolumeMount
+	for _, item := range mergeByName(foundNames, desiredNames, owned) {
+		if item.desired {
+			mounts = append(mounts, desired[item.index])
+		} else {
+			mounts = append(mounts, (*found)[item.index])
+		}
+	}
+	if equality.Semantic.DeepEqual(*found, mounts) {
+		return false
+	}
+	*found = mounts
+	return true
+}
+
+// mergeEnv adds or updates the desired environment variables, by name, and removes the variables owned by the operator
+// which are not desired anymore.
+func mergeEnv(found *[]corev1.EnvVar, desired []corev1.EnvVar, owned map[string]bool) bool {
+	var foundNames, desiredNames []string
+	for _, env := range *found {
+		foundNames = append(foundNames, env.Name)
+	}
+	for _, env := range desired {
+		desiredNames = append(desiredNames, env.Name)
+	}
+	var envVars []corev1.EnvVar
+	for _, item := range mergeByName(foundNames, desiredNames, owned) {
+		if item.desired {
+			envVars = append(envVars, desired[item.index])
+		} else {
+			envVars = append(envVars, (*found)[item.index])
+		}
+	}
+	if equality.Semantic.DeepEqual(*found, envVars) {
+		return false
+	}
+	*found = envVars
+	return true
+}
+
+func findContainer(containers []corev1.Container, name string) *corev1.Container {
+	for i := range containers {
+		if containers[i].Name == name {
+			return &containers[i]
+		}
+	}
+	return nil
+}
+
+// updateContainer updates the container fields owned by the operator: its ports, environment, resources, probes and
+// volume mounts. The environment variables and the volume mounts added by others are kept.
+// The image is resolved by the image change trigger, so it is only set when the live container does not have one yet.
+func updateContainer(found, desired *corev1.Container, ownedVolumes, ownedEnv map[string]bool) bool {
+	updated := false
+	if found.Image == "" {
+		found.Image = desired.Image
+		updated = true
+	}
+	if !containerPortsEqual(found.Ports, desired.Ports) {
+		found.Ports = desired.Ports
+		updated = true
+	}
+	if mergeEnv(&found.Env, desired.Env, ownedEnv) {
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.EnvFrom, desired.EnvFrom) {
+		found.EnvFrom = desired.EnvFrom
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.Resources, desired.Resources) {
+		found.Resources = desired.Resources
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.ReadinessProbe, desired.ReadinessProbe) {
+		found.ReadinessProbe = desired.ReadinessProbe
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.LivenessProbe, desired.LivenessProbe) {
+		found.LivenessProbe = desired.LivenessProbe
+		updated = true
+	}
+	if mergeVolumeMounts(&found.VolumeMounts, desired.VolumeMounts, ownedVolumes) {
+		updated = true
+	}
+	return updated
+}
+
+// updatePersistentVolumeClaim expands the claim when the size of its volume grows, the storage requests of a claim
+// cannot shrink.
+func updatePersistentVolumeClaim(found, desired *corev1.PersistentVolumeClaim) bool {
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	desiredSize := desired.Spec.Resources.Requests[corev1.ResourceStorage]
+	if foundSize, ok := found.Spec.Resources.Requests[corev1.ResourceStorage]; !ok || foundSize.Cmp(desiredSize) < 0 {
+		if found.Spec.Resources.Requests == nil {
+			found.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		found.Spec.Resources.Requests[corev1.ResourceStorage] = desiredSize
+		updated = true
+	}
+	return updated
+}
+
+func containerPortsEqual(found, desired []corev1.ContainerPort) bool {
+	if len(found) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		f, d := found[i], desired[i]
+		if f.Name != d.Name || f.ContainerPort != d.ContainerPort || protocolOrDefault(f.Protocol) != protocolOrDefault(d.Protocol) {
+			return false
+		}
+	}
+	return true
+}
+
+func protocolOrDefault(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+	return protocol
+}
+
+func updateService(found, desired *corev1.Service) bool {
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	if !servicePortsEqual(found.Spec.Ports, desired.Spec.Ports) {
+		found.Spec.Ports = desired.Spec.Ports
+		updated = true
+	}
+	if !equality.Semantic.DeepEqual(found.Spec.Selector, desired.Spec.Selector) {
+		found.Spec.Selector = desired.Spec.Selector
+		updated = true
+	}
+	return updated
+}
+
+// servicePortsEqual compares the service ports ignoring the node ports allocated by the cluster.
+func servicePortsEqual(found, desired []corev1.ServicePort) bool {
+	if len(found) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		f, d := found[i], desired[i]
+		if f.Name != d.Name || f.Port != d.Port || protocolOrDefault(f.Protocol) != protocolOrDefault(d.Protocol) ||
+			f.TargetPort != d.TargetPort {
+			return false
+		}
+	}
+	return true
+}
+
+func updateRoute(found, desired *routev1.Route) bool {
+	updated := updateObjectMeta(&found.ObjectMeta, &desired.ObjectMeta)
+	if found.Spec.To.Kind != desired.Spec.To.Kind || found.Spec.To.Name != desired.Spec.To.Name {
+		found.Spec.To.Kind = desired.Spec.To.Kind
+		found.Spec.To.Name = desired.Spec.To.Name
+		updated = true
+	}
+	if !routePortsEqual(found.Spec.Port, desired.Spec.Port) {
+		found.Spec.Port = desired.Spec.Port
+		updated = true
+	}
+	// the certificates are copied from the TLS Secret, a rotated Secret updates the route
+	if !equality.Semantic.DeepEqual(found.Spec.TLS, desired.Spec.TLS) {
+		found.Spec.TLS = desired.Spec.TLS
+		updated = true
+	}
+	// the host is generated by the router when not provided
+	if desired.Spec.Host != "" && found.Spec.Host != desired.Spec.Host {
+		found.Spec.Host = desired.Spec.Host
+		updated = true
+	}
+	if found.Spec.Path != desired.Spec.Path {
+		found.Spec.Path = desired.Spec.Path
+		updated = true
+	}
+	if wildcardPolicyOrDefault(found.Spec.WildcardPolicy) != wildcardPolicyOrDefault(desired.Spec.WildcardPolicy) {
+		found.Spec.WildcardPolicy = desired.Spec.WildcardPolicy
+		updated = true
+	}
+	return updated
+}
+
+// wildcardPolicyOrDefault returns the wildcard policy of a route, None when not set.
+func wildcardPolicyOrDefault(policy routev1.WildcardPolicyType) routev1.WildcardPolicyType {
+	if policy == "" {
+		return routev1.WildcardPolicyNone
+	}
+	return policy
+}
+
+func routePortsEqual(found, desired *routev1.RoutePort) bool {
+	if found == nil || desired == nil {
+		return found == desired
+	}
+	return found.TargetPort.String() == desired.TargetPort.String()
+}
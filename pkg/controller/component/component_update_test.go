@@ -0,0 +1,45 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMergeEnv(t *testing.T) {
+	owned := map[string]bool{"LOG_LEVEL": true, "REMOVED": true}
+
+	t.Run("variables added by others are kept", func(t *testing.T) {
+		found := []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}, {Name: "SIDECAR", Value: "1"}, {Name: "REMOVED", Value: "x"}}
+		desired := []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "NEW", Value: "y"}}
+		require.True(t, mergeEnv(&found, desired, owned))
+		require.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "SIDECAR", Value: "1"}, {Name: "NEW", Value: "y"}}, found)
+	})
+
+	t.Run("merged variables are up to date", func(t *testing.T) {
+		found := []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "SIDECAR", Value: "1"}}
+		require.False(t, mergeEnv(&found, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}, owned))
+		require.Equal(t, []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}, {Name: "SIDECAR", Value: "1"}}, found)
+	})
+
+	t.Run("variable not owned by the operator is replaced when desired", func(t *testing.T) {
+		found := []corev1.EnvVar{{Name: "SIDECAR", Value: "1"}}
+		require.True(t, mergeEnv(&found, []corev1.EnvVar{{Name: "SIDECAR", Value: "2"}}, owned))
+		require.Equal(t, []corev1.EnvVar{{Name: "SIDECAR", Value: "2"}}, found)
+	})
+}
+
+func TestMergeVolumes(t *testing.T) {
+	owned := map[string]bool{"data": true}
+	found := []corev1.Volume{{Name: "istio-envoy"}, {Name: "data"}}
+	desired := []corev1.Volume{{Name: "cache"}}
+
+	require.True(t, mergeVolumes(&found, desired, owned))
+	require.Equal(t, []corev1.Volume{{Name: "istio-envoy"}, {Name: "cache"}}, found)
+
+	mounts := []corev1.VolumeMount{{Name: "istio-envoy", MountPath: "/etc/istio"}, {Name: "data", MountPath: "/data"}}
+	require.True(t, mergeVolumeMounts(&mounts, []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}, owned))
+	require.Equal(t, []corev1.VolumeMount{{Name: "istio-envoy", MountPath: "/etc/istio"}, {Name: "cache", MountPath: "/cache"}}, mounts)
+}
@@ -0,0 +1,167 @@
+package component
+
+import (
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition types set on the Component status. Each reconcile step sets its own condition, the Ready condition
+// summarizes all of them so that `oc wait --for=condition=Ready` can be used. The BuildFailed condition is true while
+// the latest build of the Component has failed, it does not take part in the Ready condition, the BuildSucceeded
+// condition does.
+const (
+	ConditionReady               devconsoleapi.ComponentConditionType = "Ready"
+	ConditionSourceResolved      devconsoleapi.ComponentConditionType = "SourceResolved"
+	ConditionBuildTypeResolved   devconsoleapi.ComponentConditionType = "BuildTypeResolved"
+	ConditionBuilderImageReady   devconsoleapi.ComponentConditionType = "BuilderImageReady"
+	ConditionBuildSucceeded      devconsoleapi.ComponentConditionType = "BuildSucceeded"
+	ConditionBuildFailed         devconsoleapi.ComponentConditionType = "BuildFailed"
+	ConditionDeploymentAvailable devconsoleapi.ComponentConditionType = "DeploymentAvailable"
+	ConditionRouteAdmitted       devconsoleapi.ComponentConditionType = "RouteAdmitted"
+)
+
+// Reasons used in the Component conditions.
+const (
+	ReasonGitSourceFound            = "GitSourceFound"
+	ReasonGitSourceNotFound         = "GitSourceNotFound"
+	ReasonSourceSecretMissing       = "SourceSecretMissing"
+	ReasonImageImported             = "ImageImported"
+	ReasonImageImportPending        = "ImageImportPending"
+	ReasonImageImportFailed         = "ImageImportFailed"
+	ReasonBuildTypeSpecified        = "BuildTypeSpecified"
+	ReasonBuildTypeDetected         = "BuildTypeDetected"
+	ReasonBuildTypeNotSupported     = "BuildTypeNotSupported"
+	ReasonBuildTypeNotRequired      = "BuildTypeNotRequired"
+	ReasonDockerfileDetected        = "DockerfileDetected"
+	ReasonBuildStrategyNotSupported = "BuildStrategyNotSupported"
+	ReasonAnalysisPending           = "AnalysisPending"
+	ReasonAnalysisFailed            = "AnalysisFailed"
+	ReasonBuilderImageFound         = "BuilderImageFound"
+	ReasonBuilderImageNotFound      = "BuilderImageNotFound"
+	ReasonBuilderTagNotFound        = "BuilderTagNotFound"
+	ReasonBuilderImageImportPending = "BuilderImageImportPending"
+	ReasonBuildPending              = "BuildPending"
+	ReasonBuildRunning              = "BuildRunning"
+	ReasonBuildFailed               = "BuildFailed"
+	ReasonBuildNotFailed            = "BuildNotFailed"
+	ReasonWebhookSecretInvalid      = "WebhookSecretInvalid"
+	ReasonImageBuilt                = "ImageBuilt"
+	ReasonDeploymentPending         = "DeploymentPending"
+	ReasonScalingUp                 = "ScalingUp"
+	ReasonDeploymentFailed          = "DeploymentFailed"
+	ReasonReplicasAvailable         = "ReplicasAvailable"
+	ReasonAutoscalingInvalid        = "AutoscalingInvalid"
+	ReasonRollbackInvalid           = "RollbackInvalid"
+	ReasonVolumesInvalid            = "VolumesInvalid"
+	ReasonPortInvalid               = "PortInvalid"
+	ReasonKnativeServiceReady       = "KnativeServiceReady"
+	ReasonKnativeNotInstalled       = "KnativeNotInstalled"
+	ReasonRouteAdmissionPending     = "RouteAdmissionPending"
+	ReasonRouteAdmitted             = "RouteAdmitted"
+	ReasonRouteRejected             = "RouteRejected"
+	ReasonNoRoutablePort            = "NoRoutablePort"
+	ReasonRouteConfigInvalid        = "RouteConfigInvalid"
+	ReasonRouteHostConflict         = "RouteHostConflict"
+	ReasonTLSConfigInvalid          = "TLSConfigInvalid"
+	ReasonTLSSecretNotFound         = "TLSSecretNotFound"
+	ReasonAllConditionsMet          = "AllConditionsMet"
+)
+
+// getCondition returns the condition of the given type or nil if the Component does not have it.
+func getCondition(cp *devconsoleapi.Component, condType devconsoleapi.ComponentConditionType) *devconsoleapi.ComponentCondition {
+	for i := range cp.Status.Conditions {
+		if cp.Status.Conditions[i].Type == condType {
+			return &cp.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// setCondition adds or updates the condition of the given type. The transition time is only changed when the
+// status of the condition changes.
+func setCondition(cp *devconsoleapi.Component, condType devconsoleapi.ComponentConditionType, status corev1.ConditionStatus, reason, message string) {
+	condition := getCondition(cp, condType)
+	if condition == nil {
+		cp.Status.Conditions = append(cp.Status.Conditions, devconsoleapi.ComponentCondition{Type: condType})
+		condition = &cp.Status.Conditions[len(cp.Status.Conditions)-1]
+	}
+	if condition.Status != status {
+		condition.Status = status
+		condition.LastTransitionTime = metav1.Now()
+	}
+	condition.Reason = reason
+	condition.Message = message
+	condition.ObservedGeneration = cp.Generation
+}
+
+// removeCondition removes the condition of the given type, if any.
+func removeCondition(cp *devconsoleapi.Component, condType devconsoleapi.ComponentConditionType) {
+	var conditions []devconsoleapi.ComponentCondition
+	for _, condition := range cp.Status.Conditions {
+		if condition.Type != condType {
+			conditions = append(conditions, condition)
+		}
+	}
+	cp.Status.Conditions = conditions
+}
+
+// permanentError is an error reported by a condition of the Component, which cannot be solved by trying again: the
+// Component is reconciled again once its spec or the resource it refers to changes, not requeued.
+type permanentError struct {
+	error
+}
+
+// newPermanentError marks the error as permanent.
+func newPermanentError(err error) error {
+	return &permanentError{err}
+}
+
+// isPermanentError returns true when the error is permanent, false for the transient errors of the API server which
+// are requeued with backoff.
+func isPermanentError(err error) bool {
+	_, ok := err.(*permanentError)
+	return ok
+}
+
+// readyConditionTypes returns the conditions that have to be true for the Component to be ready, the build ones
+// depend on the build strategy and are not required for an image-only Component.
+func readyConditionTypes(cp *devconsoleapi.Component) []devconsoleapi.ComponentConditionType {
+	types := []devconsoleapi.ComponentConditionType{
+		ConditionSourceResolved,
+	}
+	switch {
+	case isImageComponent(cp):
+	case buildStrategyOf(cp) == BuildStrategyNone:
+		types = append(types, ConditionBuildTypeResolved)
+	case buildStrategyOf(cp) == BuildStrategySource:
+		types = append(types, ConditionBuildTypeResolved, ConditionBuilderImageReady, ConditionBuildSucceeded)
+	case buildStrategyOf(cp) == BuildStrategyDocker:
+		types = append(types, ConditionBuildTypeResolved, ConditionBuildSucceeded)
+	}
+	types = append(types, ConditionDeploymentAvailable)
+	// a Knative Service has its own route
+	if cp.Spec.Exposed && deploymentKindOf(cp) != DeploymentKindKnativeService {
+		types = append(types, ConditionRouteAdmitted)
+	}
+	return types
+}
+
+// updateReadyCondition sets the Ready condition from the other conditions. The Component is ready when all of them
+// are true, otherwise the Ready condition reports the first one which is not.
+func updateReadyCondition(cp *devconsoleapi.Component) {
+	for _, condType := range readyConditionTypes(cp) {
+		condition := getCondition(cp, condType)
+		if condition == nil {
+			setCondition(cp, ConditionReady, corev1.ConditionFalse, string(condType)+"Unknown", "condition "+string(condType)+" is not set yet")
+			return
+		}
+		if condition.Status != corev1.ConditionTrue {
+			setCondition(cp, ConditionReady, corev1.ConditionFalse, condition.Reason, condition.Message)
+			return
+		}
+	}
+	setCondition(cp, ConditionReady, corev1.ConditionTrue, ReasonAllConditionsMet, "")
+}
@@ -0,0 +1,81 @@
+package component
+
+import (
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Delays of the default probes, the liveness probe leaves more time to the application to start.
+const (
+	defaultReadinessDelaySeconds = 5
+	defaultLivenessDelaySeconds  = 30
+)
+
+// newContainer returns the container of the Component running the image of the output image stream, with its
+// environment, resources, probes and volumes.
+func newContainer(cp *devconsoleapi.Component, name string, containerPorts []corev1.ContainerPort, builder *BuilderImage) corev1.Container {
+	_, volumeMounts := newPodVolumes(cp)
+	return corev1.Container{
+		Name:           name,
+		Image:          name + ":latest",
+		Ports:          containerPorts,
+		Env:            cp.Spec.Env,
+		EnvFrom:        cp.Spec.EnvFrom,
+		Resources:      newResources(cp, builder),
+		ReadinessProbe: newProbe(cp.Spec.ReadinessProbe, primaryPort(cp, containerPorts), defaultReadinessDelaySeconds),
+		LivenessProbe:  newProbe(cp.Spec.LivenessProbe, primaryPort(cp, containerPorts), defaultLivenessDelaySeconds),
+		VolumeMounts:   volumeMounts,
+	}
+}
+
+// newResources returns the resources of the Component's container: the ones of its spec or else the default ones of
+// its builder image in the catalog. No resources are set otherwise, the limit ranges of the namespace apply.
+func newResources(cp *devconsoleapi.Component, builder *BuilderImage) corev1.ResourceRequirements {
+	if cp.Spec.Resources != nil {
+		return *cp.Spec.Resources
+	}
+	if builder != nil && builder.Resources != nil {
+		return *builder.Resources
+	}
+	return corev1.ResourceRequirements{}
+}
+
+// newProbe returns the probe of the Component's spec or else a TCP probe on its primary port, with the defaults of the
+// API server so that the live probe does not drift from the desired one. It returns nil without a primary port.
+func newProbe(probe *corev1.Probe, port *corev1.ContainerPort, initialDelaySeconds int32) *corev1.Probe {
+	if probe != nil {
+		return probeWithDefaults(probe.DeepCopy())
+	}
+	if port == nil {
+		return nil
+	}
+	return probeWithDefaults(&corev1.Probe{
+		Handler: corev1.Handler{
+			TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(int(port.ContainerPort))},
+		},
+		InitialDelaySeconds: initialDelaySeconds,
+	})
+}
+
+// probeWithDefaults sets the fields of the probe defaulted by the API server.
+func probeWithDefaults(probe *corev1.Probe) *corev1.Probe {
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	if probe.HTTPGet != nil && probe.HTTPGet.Scheme == "" {
+		probe.HTTPGet.Scheme = corev1.URISchemeHTTP
+	}
+	return probe
+}
@@ -0,0 +1,75 @@
+package component
+
+import (
+	"testing"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestNewContainer(t *testing.T) {
+	cp := &devconsoleapi.Component{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name,
+			Namespace: Namespace,
+		},
+		Spec: devconsoleapi.ComponentSpec{
+			BuildType:    "python",
+			GitSourceRef: "my-git-source",
+		},
+	}
+	ports := namedPorts([]corev1.ContainerPort{{ContainerPort: 9090, Protocol: corev1.ProtocolUDP}, {ContainerPort: 8080}})
+	catalog := parseBuilderCatalog(newBuilderCatalog(Namespace, map[string]string{
+		"python": `
+image: centos/python-36-centos7:latest
+resources:
+  requests:
+    memory: 256Mi
+  limits:
+    memory: 512Mi
+`,
+	}))
+	builder := catalog["python"]
+
+	t.Run("with default probes and catalog resources", func(t *testing.T) {
+		container := newContainer(cp, Name, ports, &builder)
+		require.NotNil(t, container.ReadinessProbe, "readiness probe should be set")
+		require.Equal(t, intstr.FromInt(8080), container.ReadinessProbe.TCPSocket.Port, "readiness probe should check the primary port")
+		require.Equal(t, int32(defaultReadinessDelaySeconds), container.ReadinessProbe.InitialDelaySeconds)
+		require.Equal(t, int32(10), container.ReadinessProbe.PeriodSeconds, "probe should have the defaults of the api server")
+		require.NotNil(t, container.LivenessProbe, "liveness probe should be set")
+		require.Equal(t, int32(defaultLivenessDelaySeconds), container.LivenessProbe.InitialDelaySeconds)
+		require.Equal(t, resource.MustParse("512Mi"), container.Resources.Limits[corev1.ResourceMemory])
+		require.Equal(t, resource.MustParse("256Mi"), container.Resources.Requests[corev1.ResourceMemory])
+	})
+
+	t.Run("with probes and resources of the spec", func(t *testing.T) {
+		cpWithSettings := cp.DeepCopy()
+		cpWithSettings.Spec.Resources = &corev1.ResourceRequirements{
+			Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+		}
+		cpWithSettings.Spec.ReadinessProbe = &corev1.Probe{
+			Handler: corev1.Handler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: intstr.FromInt(8080)}},
+		}
+		container := newContainer(cpWithSettings, Name, ports, &builder)
+		require.Equal(t, "/healthz", container.ReadinessProbe.HTTPGet.Path)
+		require.Equal(t, corev1.URISchemeHTTP, container.ReadinessProbe.HTTPGet.Scheme)
+		require.Empty(t, cpWithSettings.Spec.ReadinessProbe.HTTPGet.Scheme, "component spec should not be modified")
+		require.NotNil(t, container.LivenessProbe.TCPSocket, "default liveness probe should be kept")
+		require.Equal(t, *cpWithSettings.Spec.Resources, container.Resources, "resources of the spec should replace the catalog ones")
+	})
+
+	t.Run("without tcp port", func(t *testing.T) {
+		container := newContainer(cp, Name, namedPorts([]corev1.ContainerPort{{ContainerPort: 9090, Protocol: corev1.ProtocolUDP}}), nil)
+		require.Nil(t, container.ReadinessProbe, "udp port should not be probed")
+		require.Nil(t, container.LivenessProbe, "udp port should not be probed")
+		require.Empty(t, container.Resources.Limits, "no resources should be set without catalog")
+	})
+}
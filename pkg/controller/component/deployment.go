@@ -0,0 +1,229 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Kinds of the object deploying a Component.
+const (
+	// DeploymentKindDeploymentConfig deploys the component with an OpenShift DeploymentConfig, the default.
+	DeploymentKindDeploymentConfig = "DeploymentConfig"
+	// DeploymentKindDeployment deploys the component with a Kubernetes Deployment.
+	DeploymentKindDeployment = "Deployment"
+)
+
+// imageTriggersAnnotation sets the image triggers of a Kubernetes Deployment, resolving the image of its containers
+// from image stream tags like the image change trigger of a DeploymentConfig.
+const imageTriggersAnnotation = "image.openshift.io/triggers"
+
+// deploymentKindOf returns the kind of the object deploying the Component.
+func deploymentKindOf(cp *devconsoleapi.Component) string {
+	switch cp.Spec.DeploymentKind {
+	case DeploymentKindDeployment, DeploymentKindKnativeService:
+		return cp.Spec.DeploymentKind
+	}
+	return DeploymentKindDeploymentConfig
+}
+
+// reconcileDeployment creates or updates the object deploying the Component, either a DeploymentConfig or a
+// Deployment. The object of the other kinds, left when the Component switched from one to the other, is deleted.
+func (r *ReconcileComponent) reconcileDeployment(cp *devconsoleapi.Component, outputIS *imagev1.ImageStream, ports []corev1.ContainerPort) error {
+	if err := r.deleteKnativeService(cp); err != nil {
+		return err
+	}
+	if err := validateAutoscaling(cp); err != nil {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonAutoscalingInvalid, err.Error())
+		return nil
+	}
+	if err := validateVolumes(cp); err != nil {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonVolumesInvalid, err.Error())
+		return nil
+	}
+	if err := r.reconcileVolumes(cp); err != nil {
+		return err
+	}
+	configHash, err := r.GetConfigHash(cp)
+	if err != nil {
+		return err
+	}
+	builder, err := r.deploymentBuilderImage(cp)
+	if err != nil {
+		return err
+	}
+	rollback, ok, err := r.GetRollbackPodSpec(cp)
+	if err != nil || !ok {
+		return err
+	}
+	if deploymentKindOf(cp) == DeploymentKindDeployment {
+		if err := r.deleteControlled(cp, &v1.DeploymentConfig{}); err != nil {
+			return err
+		}
+		if _, err := r.CreateDeployment(cp, outputIS, ports, builder, configHash, rollback); err != nil {
+			return err
+		}
+		observeRedeploy(cp)
+		return r.reconcileAutoscaler(cp)
+	}
+	if err := r.deleteControlled(cp, &appsv1.Deployment{}); err != nil {
+		return err
+	}
+	if _, err = r.CreateDeploymentConfig(cp, outputIS, ports, builder, configHash, rollback); err != nil {
+		return err
+	}
+	observeRedeploy(cp)
+	return r.reconcileAutoscaler(cp)
+}
+
+// deploymentBuilderImage returns the builder image of a Component built with the source strategy, which provides the
+// default resources of its container, or nil for the other Components.
+func (r *ReconcileComponent) deploymentBuilderImage(cp *devconsoleapi.Component) (*BuilderImage, error) {
+	if isImageComponent(cp) || buildStrategyOf(cp) != BuildStrategySource {
+		return nil, nil
+	}
+	return r.GetBuilderImage(cp)
+}
+
+// deleteControlled deletes the object of the given type named after the Component, if it is controlled by it.
+func (r *ReconcileComponent) deleteControlled(cp *devconsoleapi.Component, obj runtime.Object) error {
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: cp.Name, Namespace: cp.Namespace}, obj)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if object, ok := obj.(metav1.Object); !ok || !metav1.IsControlledBy(object, cp) {
+		return nil
+	}
+	return r.delete(obj)
+}
+
+// CreateDeployment creates or updates a Kubernetes Deployment used instead of a DeploymentConfig. The pod spec of a
+// previous revision replaces the desired one while the component is rolled back.
+func (r *ReconcileComponent) CreateDeployment(cp *devconsoleapi.Component, outputIS *imagev1.ImageStream, containerPorts []corev1.ContainerPort, builder *BuilderImage, configHash string, rollback *corev1.PodSpec) (*appsv1.Deployment, error) {
+	d := newDeployment(cp, outputIS, containerPorts, builder, configHash)
+	if rollback != nil {
+		rollBackDeployment(d, outputIS.Name, rollback)
+	}
+	if err := controllerutil.SetControllerReference(cp, d, r.scheme); err != nil {
+		log.Error(err, "** Setting owner reference fails **")
+		return nil, err
+	}
+	foundD := &appsv1.Deployment{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: d.Name, Namespace: d.Namespace}, foundD)
+	if err == nil {
+		// the replicas of an autoscaled component are set by its autoscaler
+		if isAutoscaled(cp) {
+			d.Spec.Replicas = foundD.Spec.Replicas
+		}
+		updated := updateDeployment(foundD, d)
+		if rollback != nil && rollBackImages(&foundD.Spec.Template.Spec, rollback) {
+			updated = true
+		}
+		if !updated {
+			log.Info("** Skip Updating Deployment: Already up to date", "Deployment.Namespace", foundD.Namespace, "Deployment.Name", foundD.Name)
+			return foundD, nil
+		}
+		log.Info("💡💡  Updating Deployment 💡💡", "Deployment.Namespace", foundD.Namespace, "Deployment.Name", foundD.Name)
+		if err := r.client.Update(context.TODO(), foundD); err != nil {
+			log.Error(err, "** Deployment update fails **")
+			return nil, err
+		}
+		return foundD, nil
+	}
+	if errors.IsNotFound(err) {
+		log.Info("💡💡  Creating a new Deployment 💡💡", "Deployment.Namespace", d.Namespace, "Deployment.Name", d.Name)
+		err := r.client.Create(context.TODO(), d)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			log.Error(err, "** Deployment creation fails **")
+			return nil, err
+		}
+		return d, nil
+	}
+	return nil, err
+}
+
+// ObserveDeployment watches for secondary resource Deployment, the equivalent of ObserveDeploymentConfig.
+func (r *ReconcileComponent) ObserveDeployment(cp *devconsoleapi.Component, dList *appsv1.DeploymentList) error {
+	lbls := map[string]string{
+		"app": cp.Name,
+	}
+	opts := client.ListOptions{
+		Namespace:     cp.Namespace,
+		LabelSelector: labels.SelectorFromSet(lbls),
+	}
+	err := r.client.List(context.TODO(),
+		&opts,
+		dList)
+	if err != nil {
+		log.Error(err, "failed to list existing Deployment")
+		return err
+	}
+
+	if len(dList.Items) == 0 {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionUnknown, ReasonDeploymentPending, "Deployment is not created yet")
+		return nil
+	}
+	phase := devconsoleapi.PhaseDeployed
+	var unavailable, failed []string
+	for _, d := range dList.Items {
+		replicas := int32(1)
+		if d.Spec.Replicas != nil {
+			replicas = *d.Spec.Replicas
+		}
+		desired := r.desiredReplicas(cp, replicas)
+		observeRollout(cp, deploymentRevision(d.Annotations), d.Status.ReadyReplicas)
+		if deploymentCondition(&d, appsv1.DeploymentProgressing, corev1.ConditionFalse) != nil {
+			log.Info(fmt.Sprintf("👻👻  Deployment %s is not progressing 👻👻", d.Name))
+			phase = devconsoleapi.PhaseDeploying
+			failed = append(failed, fmt.Sprintf("Deployment %s: %s", d.Name, deploymentCondition(&d, appsv1.DeploymentProgressing, corev1.ConditionFalse).Message))
+			continue
+		}
+		if deploymentCondition(&d, appsv1.DeploymentAvailable, corev1.ConditionTrue) == nil || d.Status.AvailableReplicas < desired {
+			log.Info(fmt.Sprintf("👻👻  Scaling up Deployment %s 👻👻", d.Name))
+			phase = devconsoleapi.PhaseDeploying
+			unavailable = append(unavailable, fmt.Sprintf("Deployment %s has %d/%d available replicas", d.Name, d.Status.AvailableReplicas, desired))
+			continue
+		}
+		log.Info(fmt.Sprintf("✨✨ Stable Deployment %s ✨✨", d.Name))
+	}
+	cp.Status.Phase = phase
+	switch {
+	case len(failed) > 0:
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonDeploymentFailed, strings.Join(failed, ", "))
+	case len(unavailable) > 0:
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonScalingUp, strings.Join(unavailable, ", "))
+	default:
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionTrue, ReasonReplicasAvailable, "all replicas are available")
+	}
+	return nil
+}
+
+// deploymentCondition returns the condition of the Deployment with the given type and status, or nil.
+func deploymentCondition(d *appsv1.Deployment, condType appsv1.DeploymentConditionType, status corev1.ConditionStatus) *appsv1.DeploymentCondition {
+	for i := range d.Status.Conditions {
+		if d.Status.Conditions[i].Type == condType && d.Status.Conditions[i].Status == status {
+			return &d.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package component
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// configHashAnnotation is set on the pod template of the Component with a hash of the ConfigMaps and Secrets
+// referenced by its environment, so that changing one of them rolls the Component out.
+const configHashAnnotation = "devconsole.openshift.io/config-hash"
+
+// envSources returns the sorted names of the ConfigMaps and Secrets referenced by the environment of the Component.
+func envSources(cp *devconsoleapi.Component) (configMaps []string, secrets []string) {
+	cmNames := make(map[string]bool)
+	secretNames := make(map[string]bool)
+	for _, envFrom := range cp.Spec.EnvFrom {
+		if envFrom.ConfigMapRef != nil {
+			cmNames[envFrom.ConfigMapRef.Name] = true
+		}
+		if envFrom.SecretRef != nil {
+			secretNames[envFrom.SecretRef.Name] = true
+		}
+	}
+	for _, env := range cp.Spec.Env {
+		if env.ValueFrom == nil {
+			continue
+		}
+		if env.ValueFrom.ConfigMapKeyRef != nil {
+			cmNames[env.ValueFrom.ConfigMapKeyRef.Name] = true
+		}
+		if env.ValueFrom.SecretKeyRef != nil {
+			secretNames[env.ValueFrom.SecretKeyRef.Name] = true
+		}
+	}
+	return sortedKeys(cmNames), sortedKeys(secretNames)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GetConfigHash returns a hash of the data of the ConfigMaps and Secrets referenced by the environment of the
+// Component, or an empty string when it does not reference any. Missing ones are skipped, the pods wait for them.
+func (r *ReconcileComponent) GetConfigHash(cp *devconsoleapi.Component) (string, error) {
+	configMaps, secrets := envSources(cp)
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return "", nil
+	}
+	hash := sha256.New()
+	for _, name := range configMaps {
+		cm := &corev1.ConfigMap{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cp.Namespace}, cm)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		data := make(map[string][]byte)
+		for k, v := range cm.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range cm.BinaryData {
+			data[k] = v
+		}
+		writeHashData(hash, "configmap/"+name, data)
+	}
+	for _, name := range secrets {
+		secret := &corev1.Secret{}
+		err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cp.Namespace}, secret)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		writeHashData(hash, "secret/"+name, secret.Data)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// writeHashData writes the data of a ConfigMap or Secret, sorted by key, to the hash.
+func writeHashData(w io.Writer, source string, data map[string][]byte) {
+	fmt.Fprintf(w, "%s\n", source)
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s=%d:%s\n", k, len(data[k]), data[k])
+	}
+}
+
+// newEnvSourceMapper returns the Components to reconcile when a ConfigMap changes, the ones referencing it in their
+// environment so that they are rolled out. The Secrets are mapped by newSecretMapper.
+func newEnvSourceMapper(cl client.Client) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		cpList := &devconsoleapi.ComponentList{}
+		if err := cl.List(context.TODO(), &client.ListOptions{Namespace: obj.Meta.GetNamespace()}, cpList); err != nil {
+			log.Error(err, "** failed to list components using the configmap **")
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, cp := range cpList.Items {
+			configMaps, _ := envSources(&cp)
+			if !containsString(configMaps, obj.Meta.GetName()) {
+				continue
+			}
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: cp.Namespace, Name: cp.Name},
+			})
+		}
+		return requests
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
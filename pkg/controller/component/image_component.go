@@ -0,0 +1,66 @@
+package component
+
+import (
+	"fmt"
+
+	imagev1 "github.com/openshift/api/image/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// isImageComponent returns true when the Component deploys an external image instead of building its GitSource.
+func isImageComponent(cp *devconsoleapi.Component) bool {
+	return cp.Spec.Image != ""
+}
+
+// reconcileImage creates or updates the output image stream importing the external image of an image-only Component.
+// Its BuildConfig, if any, is removed. It returns the output image stream and the ports exposed by the image, or no
+// image stream while the image is not imported yet.
+func (r *ReconcileComponent) reconcileImage(cp *devconsoleapi.Component) (*imagev1.ImageStream, []corev1.ContainerPort, error) {
+	cp.Status.BuildStrategy = ""
+	cp.Status.BuildType = ""
+	removeCondition(cp, ConditionBuildTypeResolved)
+	removeCondition(cp, ConditionBuilderImageReady)
+	removeCondition(cp, ConditionBuildSucceeded)
+	if err := r.DeleteBuildConfig(cp); err != nil {
+		return nil, nil, err
+	}
+	outputIS, err := r.CreateOutputImageStream(cp)
+	if err != nil {
+		setCondition(cp, ConditionSourceResolved, corev1.ConditionFalse, ReasonImageImportFailed, err.Error())
+		return nil, nil, err
+	}
+	if !r.ObserveImageImport(cp, outputIS) {
+		return nil, nil, nil
+	}
+	ports, err := r.GetExposedPorts(cp, "latest", outputIS, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return outputIS, ports, nil
+}
+
+// ObserveImageImport checks whether the external image of the Component has been imported in its output image stream.
+// It returns false while the import is pending or when it failed.
+func (r *ReconcileComponent) ObserveImageImport(cp *devconsoleapi.Component, is *imagev1.ImageStream) bool {
+	for _, tag := range is.Status.Tags {
+		if tag.Tag != "latest" {
+			continue
+		}
+		if len(tag.Items) > 0 {
+			setCondition(cp, ConditionSourceResolved, corev1.ConditionTrue, ReasonImageImported, fmt.Sprintf("image %s imported", cp.Spec.Image))
+			return true
+		}
+		for _, condition := range tag.Conditions {
+			if condition.Type == imagev1.ImportSuccess && condition.Status == corev1.ConditionFalse {
+				log.Info(fmt.Sprintf("** Import of image %s fails: %s **", cp.Spec.Image, condition.Message))
+				setCondition(cp, ConditionSourceResolved, corev1.ConditionFalse, ReasonImageImportFailed, condition.Message)
+				return false
+			}
+		}
+	}
+	setCondition(cp, ConditionSourceResolved, corev1.ConditionUnknown, ReasonImageImportPending, fmt.Sprintf("image %s is being imported", cp.Spec.Image))
+	return false
+}
@@ -0,0 +1,333 @@
+package component
+
+import (
+	"context"
+	"fmt"
+
+	v1 "github.com/openshift/api/apps/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+	routev1 "github.com/openshift/api/route/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"github.com/redhat-developer/devconsole-operator/pkg/resource"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta1 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// DeploymentKindKnativeService deploys the component as a Knative Service, which provides its own route and scales
+// to zero, instead of a DeploymentConfig with a Service and a Route.
+const DeploymentKindKnativeService = "KnativeService"
+
+// knativeServiceGVK is the kind of the Knative Services. The Knative types are handled as unstructured objects so that
+// the operator does not depend on Knative being installed in the cluster.
+var knativeServiceGVK = schema.GroupVersionKind{Group: "serving.knative.dev", Version: "v1", Kind: "Service"}
+
+// newKnativeService returns an empty Knative Service, used to get or watch them.
+func newKnativeService() *unstructured.Unstructured {
+	ksvc := &unstructured.Unstructured{}
+	ksvc.SetGroupVersionKind(knativeServiceGVK)
+	return ksvc
+}
+
+// newKnativeServiceList returns an empty list of Knative Services.
+func newKnativeServiceList() *unstructured.UnstructuredList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(knativeServiceGVK.GroupVersion().WithKind(knativeServiceGVK.Kind + "List"))
+	return list
+}
+
+// builtImage returns the pull spec, with its digest, of the latest image of the output image stream, or an empty
+// string when no image has been built or imported yet.
+func builtImage(is *imagev1.ImageStream) string {
+	for _, tag := range is.Status.Tags {
+		if tag.Tag == "latest" && len(tag.Items) > 0 {
+			return tag.Items[0].DockerImageReference
+		}
+	}
+	return ""
+}
+
+// knativeInstalled returns true when the cluster serves the Knative Services.
+func (r *ReconcileComponent) knativeInstalled() (bool, error) {
+	_, err := r.restMapper.RESTMapping(knativeServiceGVK.GroupKind(), knativeServiceGVK.Version)
+	if meta.IsNoMatchError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// reconcileKnativeService creates or updates the Knative Service of the Component, which replaces its deployment,
+// Service and Route: the ones created before the Component switched to Knative are deleted. The Component is
+// validated, and Knative Serving looked up, before anything is deleted so that a running application is left as is
+// when it cannot be deployed by Knative. The Knative Service runs the digest of the latest image of the output image
+// stream, it is only created once this image exists.
+func (r *ReconcileComponent) reconcileKnativeService(cp *devconsoleapi.Component, outputIS *imagev1.ImageStream, ports []corev1.ContainerPort) error {
+	if err := validateAutoscaling(cp); err != nil {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonAutoscalingInvalid, err.Error())
+		return nil
+	}
+	if err := validateVolumes(cp); err != nil {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonVolumesInvalid, err.Error())
+		return nil
+	}
+	installed, err := r.knativeInstalled()
+	if err != nil {
+		return err
+	}
+	if !installed {
+		log.Info("** Knative Serving is not installed, the component cannot be deployed as a Knative Service **")
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonKnativeNotInstalled, "Knative Serving is not installed in the cluster")
+		return nil
+	}
+	for _, obj := range []runtime.Object{&v1.DeploymentConfig{}, &appsv1.Deployment{}, &corev1.Service{}, &routev1.Route{}} {
+		if err := r.deleteControlled(cp, obj); err != nil {
+			return err
+		}
+	}
+	removeCondition(cp, ConditionRouteAdmitted)
+	// Knative scales the revisions itself, within the bounds annotated on the template
+	if err := r.deleteControlled(cp, &autoscalingv2beta1.HorizontalPodAutoscaler{}); err != nil {
+		return err
+	}
+	// the PersistentVolumeClaims left by a previous deployment are deleted
+	if err := r.reconcileVolumes(cp); err != nil {
+		return err
+	}
+	image := builtImage(outputIS)
+	if image == "" {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionUnknown, ReasonDeploymentPending, fmt.Sprintf("waiting for an image in ImageStream %s", outputIS.Name))
+		return nil
+	}
+	configHash, err := r.GetConfigHash(cp)
+	if err != nil {
+		return err
+	}
+	builder, err := r.deploymentBuilderImage(cp)
+	if err != nil {
+		return err
+	}
+	if _, err := r.CreateKnativeService(cp, image, ports, builder, configHash); err != nil {
+		return err
+	}
+	observeRedeploy(cp)
+	return nil
+}
+
+// newKnativeServiceFor returns the Knative Service running the given image of the Component. A new revision is
+// created when the hash of the configuration of its environment or the bounds of its autoscaler change.
+func newKnativeServiceFor(cp *devconsoleapi.Component, image string, containerPorts []corev1.ContainerPort, builder *BuilderImage, configHash string) *unstructured.Unstructured {
+	ksvc := newKnativeService()
+	ksvc.SetName(cp.Name)
+	ksvc.SetNamespace(cp.Namespace)
+	ksvc.SetLabels(labelsForComponent(cp))
+	ksvc.SetAnnotations(resource.GetAnnotationsForCR(cp))
+	container := knativeContainerFor(cp, image, containerPorts, builder)
+	podLabels := map[string]interface{}{}
+	for k, v := range resource.GetLabelsForCR(cp) {
+		podLabels[k] = v
+	}
+	podMeta := map[string]interface{}{
+		"labels": podLabels,
+	}
+	podAnnotations := map[string]interface{}{}
+	if configHash != "" {
+		podAnnotations[configHashAnnotation] = configHash
+	}
+	for k, v := range newKnativeScaleAnnotations(cp) {
+		podAnnotations[k] = v
+	}
+	if redeploy := cp.Annotations[redeployAnnotation]; redeploy != "" {
+		podAnnotations[redeployAnnotation] = redeploy
+	}
+	if len(podAnnotations) > 0 {
+		podMeta["annotations"] = podAnnotations
+	}
+	ksvc.Object["spec"] = map[string]interface{}{
+		"template": map[string]interface{}{
+			"metadata": podMeta,
+			"spec": map[string]interface{}{
+				"containers": []interface{}{container},
+			},
+		},
+	}
+	return ksvc
+}
+
+// updateKnativeService updates the labels, annotations, image, port, environment and resources of the live Knative
+// Service, the fields defaulted by Knative are left untouched.
+func updateKnativeService(found, desired *unstructured.Unstructured) bool {
+	objectMeta := metav1.ObjectMeta{Labels: found.GetLabels(), Annotations: found.GetAnnotations()}
+	updated := updateObjectMeta(&objectMeta, &metav1.ObjectMeta{Labels: desired.GetLabels(), Annotations: desired.GetAnnotations()})
+	found.SetLabels(objectMeta.Labels)
+	found.SetAnnotations(objectMeta.Annotations)
+	desiredLabels, _, _ := unstructured.NestedStringMap(desired.Object, "spec", "template", "metadata", "labels")
+	foundLabels, _, _ := unstructured.NestedStringMap(found.Object, "spec", "template", "metadata", "labels")
+	if !equality.Semantic.DeepEqual(foundLabels, desiredLabels) {
+		_ = unstructured.SetNestedStringMap(found.Object, desiredLabels, "spec", "template", "metadata", "labels")
+		updated = true
+	}
+	for _, annotation := range []string{configHashAnnotation, knativeMinScaleAnnotation, knativeMaxScaleAnnotation, redeployAnnotation} {
+		desiredValue, _, _ := unstructured.NestedString(desired.Object, "spec", "template", "metadata", "annotations", annotation)
+		foundValue, _, _ := unstructured.NestedString(found.Object, "spec", "template", "metadata", "annotations", annotation)
+		if foundValue == desiredValue {
+			continue
+		}
+		if desiredValue == "" {
+			unstructured.RemoveNestedField(found.Object, "spec", "template", "metadata", "annotations", annotation)
+		} else {
+			_ = unstructured.SetNestedField(found.Object, desiredValue, "spec", "template", "metadata", "annotations", annotation)
+		}
+		updated = true
+	}
+	desiredContainer := knativeContainer(desired)
+	foundContainer := knativeContainer(found)
+	if foundContainer == nil {
+		_ = unstructured.SetNestedSlice(found.Object, []interface{}{desiredContainer}, "spec", "template", "spec", "containers")
+		return true
+	}
+	for _, field := range []string{"image", "ports", "env", "envFrom", "resources"} {
+		if !equality.Semantic.DeepEqual(foundContainer[field], desiredContainer[field]) {
+			if desiredContainer[field] == nil {
+				delete(foundContainer, field)
+			} else {
+				foundContainer[field] = desiredContainer[field]
+			}
+			updated = true
+		}
+	}
+	return updated
+}
+
+// knativeContainerFor returns the content of the container of the Knative Service, running the image with the
+// environment and resources of the Component. A Knative Service exposes a single, unnamed, port and probes it itself.
+func knativeContainerFor(cp *devconsoleapi.Component, image string, containerPorts []corev1.ContainerPort, builder *BuilderImage) map[string]interface{} {
+	c := corev1.Container{
+		Image:     image,
+		Env:       cp.Spec.Env,
+		EnvFrom:   cp.Spec.EnvFrom,
+		Resources: newResources(cp, builder),
+	}
+	if port := primaryPort(cp, containerPorts); port != nil {
+		c.Ports = []corev1.ContainerPort{{ContainerPort: port.ContainerPort}}
+	}
+	container, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&c)
+	if err != nil {
+		log.Error(err, "** failed to convert the container of the knative service **")
+		return map[string]interface{}{"image": image}
+	}
+	// the fields left empty are not set by the operator
+	delete(container, "name")
+	if resources, ok := container["resources"].(map[string]interface{}); ok && len(resources) == 0 {
+		delete(container, "resources")
+	}
+	return container
+}
+
+// knativeContainer returns the single container of the Knative Service, or nil if it has none.
+func knativeContainer(ksvc *unstructured.Unstructured) map[string]interface{} {
+	containers, _, _ := unstructured.NestedFieldNoCopy(ksvc.Object, "spec", "template", "spec", "containers")
+	list, ok := containers.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil
+	}
+	container, _ := list[0].(map[string]interface{})
+	return container
+}
+
+// deleteKnativeService deletes the Knative Service left when the Component switched to another deployment kind.
+// Nothing is done on clusters without Knative.
+func (r *ReconcileComponent) deleteKnativeService(cp *devconsoleapi.Component) error {
+	err := r.deleteControlled(cp, newKnativeService())
+	if meta.IsNoMatchError(err) {
+		return nil
+	}
+	return err
+}
+
+// CreateKnativeService creates or updates the Knative Service deploying the Component.
+func (r *ReconcileComponent) CreateKnativeService(cp *devconsoleapi.Component, image string, containerPorts []corev1.ContainerPort, builder *BuilderImage, configHash string) (*unstructured.Unstructured, error) {
+	ksvc := newKnativeServiceFor(cp, image, containerPorts, builder, configHash)
+	if err := controllerutil.SetControllerReference(cp, ksvc, r.scheme); err != nil {
+		log.Error(err, "** Setting owner reference fails **")
+		return nil, err
+	}
+	found := newKnativeService()
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: ksvc.GetName(), Namespace: ksvc.GetNamespace()}, found)
+	if err == nil {
+		if !updateKnativeService(found, ksvc) {
+			log.Info("** Skip Updating Knative Service: Already up to date", "Service.Namespace", found.GetNamespace(), "Service.Name", found.GetName())
+			return found, nil
+		}
+		log.Info("💡💡  Updating Knative Service 💡💡", "Service.Namespace", found.GetNamespace(), "Service.Name", found.GetName())
+		if err := r.client.Update(context.TODO(), found); err != nil {
+			log.Error(err, "** Knative Service update fails **")
+			return nil, err
+		}
+		return found, nil
+	}
+	if errors.IsNotFound(err) {
+		log.Info("💡💡  Creating a new Knative Service 💡💡", "Service.Namespace", ksvc.GetNamespace(), "Service.Name", ksvc.GetName())
+		err := r.client.Create(context.TODO(), ksvc)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			log.Error(err, "** Knative Service creation fails **")
+			return nil, err
+		}
+		return ksvc, nil
+	}
+	return nil, err
+}
+
+// ObserveKnativeService reports the readiness of the Knative Service of the Component and records its URL.
+func (r *ReconcileComponent) ObserveKnativeService(cp *devconsoleapi.Component) error {
+	ksvc := newKnativeService()
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: cp.Name, Namespace: cp.Namespace}, ksvc)
+	if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		cp.Status.URL = ""
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionUnknown, ReasonDeploymentPending, "Knative Service is not created yet")
+		return nil
+	}
+	if err != nil {
+		log.Error(err, "failed to get existing Knative Service")
+		return err
+	}
+	url, _, _ := unstructured.NestedString(ksvc.Object, "status", "url")
+	cp.Status.URL = url
+	conditions, _, _ := unstructured.NestedSlice(ksvc.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != "Ready" {
+			continue
+		}
+		message, _ := condition["message"].(string)
+		switch condition["status"] {
+		case string(corev1.ConditionTrue):
+			log.Info(fmt.Sprintf("✨✨ Ready Knative Service %s ✨✨", ksvc.GetName()))
+			cp.Status.Phase = devconsoleapi.PhaseDeployed
+			setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionTrue, ReasonKnativeServiceReady, fmt.Sprintf("Knative Service %s is ready at %s", ksvc.GetName(), url))
+		case string(corev1.ConditionFalse):
+			cp.Status.Phase = devconsoleapi.PhaseDeploying
+			setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonDeploymentFailed, message)
+		default:
+			cp.Status.Phase = devconsoleapi.PhaseDeploying
+			setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionUnknown, ReasonDeploymentPending, message)
+		}
+		return nil
+	}
+	cp.Status.Phase = devconsoleapi.PhaseDeploying
+	setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionUnknown, ReasonDeploymentPending, fmt.Sprintf("Knative Service %s is not ready yet", ksvc.GetName()))
+	return nil
+}
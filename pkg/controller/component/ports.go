@@ -0,0 +1,67 @@
+package component
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// defaultPort is exposed by the Component when no port is declared nor detected.
+const defaultPort = 8080
+
+// portName returns the name of a port of the Component, shared by its container, its Service and its Route.
+func portName(port int32, protocol corev1.Protocol) string {
+	return fmt.Sprintf("%d-%s", port, strings.ToLower(string(protocolOrDefault(protocol))))
+}
+
+// namedPorts returns the container ports sorted by number and protocol, without duplicates, each one named after its
+// number and protocol. The default port is returned when there is none.
+func namedPorts(ports []corev1.ContainerPort) []corev1.ContainerPort {
+	if len(ports) == 0 {
+		ports = []corev1.ContainerPort{{ContainerPort: defaultPort}}
+	}
+	seen := make(map[string]bool)
+	var named []corev1.ContainerPort
+	for _, port := range ports {
+		name := portName(port.ContainerPort, port.Protocol)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		named = append(named, corev1.ContainerPort{
+			Name:          name,
+			ContainerPort: port.ContainerPort,
+			Protocol:      protocolOrDefault(port.Protocol),
+		})
+	}
+	sort.Slice(named, func(i, j int) bool {
+		if named[i].ContainerPort != named[j].ContainerPort {
+			return named[i].ContainerPort < named[j].ContainerPort
+		}
+		return named[i].Protocol < named[j].Protocol
+	})
+	return named
+}
+
+// primaryPort returns the port exposed by the Route and the Knative Service of the Component: the port of its spec
+// or else the lowest TCP port. It returns nil when the Component has no TCP port, which cannot be routed.
+func primaryPort(cp *devconsoleapi.Component, ports []corev1.ContainerPort) *corev1.ContainerPort {
+	for i, port := range ports {
+		if protocolOrDefault(port.Protocol) == corev1.ProtocolTCP && (cp.Spec.Port == 0 || port.ContainerPort == cp.Spec.Port) {
+			return &ports[i]
+		}
+	}
+	return nil
+}
+
+// validatePort checks the port set in the spec of the Component, which must not be a privileged port.
+func validatePort(cp *devconsoleapi.Component) error {
+	if cp.Spec.Port != 0 && (cp.Spec.Port > 65535 || cp.Spec.Port < 1024) {
+		return fmt.Errorf("port %d is out of range [1024-65535]", cp.Spec.Port)
+	}
+	return nil
+}
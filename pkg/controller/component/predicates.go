@@ -0,0 +1,103 @@
+package component
+
+import (
+	buildv1 "github.com/openshift/api/build/v1"
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// ignoreStatusUpdates filters out the updates of a Component which did not change its spec or metadata. With the
+// status subresource enabled, the generation is only incremented when the spec changes, so the status updates made
+// by the controller itself do not trigger a new reconciliation.
+var ignoreStatusUpdates = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.MetaOld == nil || e.MetaNew == nil {
+			return true
+		}
+		return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration() ||
+			!equality.Semantic.DeepEqual(e.MetaOld.GetLabels(), e.MetaNew.GetLabels()) ||
+			!equality.Semantic.DeepEqual(e.MetaOld.GetAnnotations(), e.MetaNew.GetAnnotations())
+	},
+}
+
+// isControlledByComponent returns true when the object is controlled by a Component.
+func isControlledByComponent(object metav1.Object) bool {
+	if object == nil {
+		return false
+	}
+	owner := metav1.GetControllerOf(object)
+	return owner != nil && owner.Kind == "Component" && owner.APIVersion == devconsoleapi.SchemeGroupVersion.String()
+}
+
+// isOwnedByComponent returns true when the object is owned by a Component, controlling it or not.
+func isOwnedByComponent(object metav1.Object) bool {
+	if object == nil {
+		return false
+	}
+	for _, owner := range object.GetOwnerReferences() {
+		if owner.Kind == "Component" && owner.APIVersion == devconsoleapi.SchemeGroupVersion.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// componentControlledChanges filters the events of the secondary resources, keeping the ones of the resources
+// controlled by a Component. The updates sent by the periodic resyncs of the informers, which did not change the
+// resource, are filtered out too.
+var componentControlledChanges = newOwnerChanges(isControlledByComponent)
+
+// componentOwnedChanges filters the events of the secondary resources shared by several Components, like the builder
+// image streams, keeping the ones of the resources owned by a Component, the resyncs are filtered out too.
+var componentOwnedChanges = newOwnerChanges(isOwnedByComponent)
+
+// newOwnerChanges returns a predicate keeping the events of the resources matched by the owner function, without the
+// updates sent by the periodic resyncs of the informers, which did not change the resource.
+func newOwnerChanges(owned func(metav1.Object) bool) predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return owned(e.Meta)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return owned(e.Meta)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if !owned(e.MetaOld) && !owned(e.MetaNew) {
+				return false
+			}
+			return e.MetaOld == nil || e.MetaNew == nil || e.MetaOld.GetResourceVersion() != e.MetaNew.GetResourceVersion()
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return owned(e.Meta)
+		},
+	}
+}
+
+// hasBuildConfig returns true when the object is a Build started from a BuildConfig.
+func hasBuildConfig(object metav1.Object) bool {
+	return object != nil && object.GetLabels()[buildv1.BuildConfigLabel] != ""
+}
+
+// buildChanges filters the events of the Builds, keeping the ones of the Builds started from a BuildConfig. Whether
+// that BuildConfig is controlled by a Component is only known by the mapper of the watch. The updates sent by the
+// periodic resyncs of the informers are filtered out too.
+var buildChanges = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return hasBuildConfig(e.Meta)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return hasBuildConfig(e.Meta)
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if !hasBuildConfig(e.MetaOld) && !hasBuildConfig(e.MetaNew) {
+			return false
+		}
+		return e.MetaOld == nil || e.MetaNew == nil || e.MetaOld.GetResourceVersion() != e.MetaNew.GetResourceVersion()
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return hasBuildConfig(e.Meta)
+	},
+}
@@ -0,0 +1,314 @@
+package component
+
+import (
+	"testing"
+
+	appsv1 "github.com/openshift/api/apps/v1"
+	buildv1 "github.com/openshift/api/build/v1"
+	imagev1 "github.com/openshift/api/image/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/util/workqueue"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestIgnoreStatusUpdates(t *testing.T) {
+	newComponent := func(generation int64, phase string) *devconsoleapi.Component {
+		return &devconsoleapi.Component{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       Name,
+				Namespace:  Namespace,
+				Generation: generation,
+				Labels: map[string]string{
+					"app.kubernetes.io/name": Name,
+				},
+			},
+			Status: devconsoleapi.ComponentStatus{
+				Phase: phase,
+			},
+		}
+	}
+
+	t.Run("status update is ignored", func(t *testing.T) {
+		old, updated := newComponent(1, devconsoleapi.PhaseBuilding), newComponent(1, devconsoleapi.PhaseDeployed)
+		require.False(t, ignoreStatusUpdates.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: updated, ObjectNew: updated}))
+	})
+
+	t.Run("spec update is not ignored", func(t *testing.T) {
+		old, updated := newComponent(1, devconsoleapi.PhaseDeployed), newComponent(2, devconsoleapi.PhaseDeployed)
+		require.True(t, ignoreStatusUpdates.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: updated, ObjectNew: updated}))
+	})
+
+	t.Run("labels update is not ignored", func(t *testing.T) {
+		old, updated := newComponent(1, devconsoleapi.PhaseDeployed), newComponent(1, devconsoleapi.PhaseDeployed)
+		updated.Labels["app.kubernetes.io/part-of"] = "application-1"
+		require.True(t, ignoreStatusUpdates.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: updated, ObjectNew: updated}))
+	})
+}
+
+func TestComponentControlledChanges(t *testing.T) {
+	s := runtime.NewScheme()
+	s.AddKnownTypes(devconsoleapi.SchemeGroupVersion, &devconsoleapi.Component{})
+	owner := &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &devconsoleapi.Component{},
+	}
+	require.NoError(t, owner.InjectScheme(s))
+
+	newDeploymentConfig := func(resourceVersion string, controlled bool) *appsv1.DeploymentConfig {
+		dc := &appsv1.DeploymentConfig{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            Name,
+				Namespace:       Namespace,
+				ResourceVersion: resourceVersion,
+			},
+		}
+		if controlled {
+			isController := true
+			dc.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: devconsoleapi.SchemeGroupVersion.String(),
+				Kind:       "Component",
+				Name:       "my-component",
+				UID:        "5d1ad2c5-7c06-11e9-8f9e-2a86e4085a59",
+				Controller: &isController,
+			}}
+		}
+		return dc
+	}
+	// update sends the update of the DeploymentConfig through the predicate and the handler of its watch and returns
+	// the requests enqueued
+	update := func(old, updated *appsv1.DeploymentConfig) []reconcile.Request {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		defer q.ShutDown()
+		e := event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: updated, ObjectNew: updated}
+		if componentControlledChanges.Update(e) {
+			owner.Update(e, q)
+		}
+		var requests []reconcile.Request
+		for q.Len() > 0 {
+			item, _ := q.Get()
+			requests = append(requests, item.(reconcile.Request))
+			q.Done(item)
+		}
+		return requests
+	}
+
+	t.Run("change to an unrelated DeploymentConfig does not reconcile any Component", func(t *testing.T) {
+		requests := update(newDeploymentConfig("1", false), newDeploymentConfig("2", false))
+		require.Empty(t, requests)
+	})
+
+	t.Run("change to a controlled DeploymentConfig reconciles its Component", func(t *testing.T) {
+		requests := update(newDeploymentConfig("1", true), newDeploymentConfig("2", true))
+		require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "my-component", Namespace: Namespace}}}, requests)
+	})
+
+	t.Run("resync of a controlled DeploymentConfig is ignored", func(t *testing.T) {
+		requests := update(newDeploymentConfig("1", true), newDeploymentConfig("1", true))
+		require.Empty(t, requests)
+	})
+
+	t.Run("creation and deletion of an unrelated DeploymentConfig are ignored", func(t *testing.T) {
+		dc := newDeploymentConfig("1", false)
+		require.False(t, componentControlledChanges.Create(event.CreateEvent{Meta: dc, Object: dc}))
+		require.False(t, componentControlledChanges.Delete(event.DeleteEvent{Meta: dc, Object: dc}))
+	})
+
+	t.Run("deletion of a controlled DeploymentConfig is not ignored", func(t *testing.T) {
+		dc := newDeploymentConfig("1", true)
+		require.True(t, componentControlledChanges.Delete(event.DeleteEvent{Meta: dc, Object: dc}))
+	})
+}
+
+func TestBuildMapper(t *testing.T) {
+	require.NoError(t, buildv1.AddToScheme(scheme.Scheme))
+	isController := true
+	controlledBC := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name,
+			Namespace: Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: devconsoleapi.SchemeGroupVersion.String(),
+				Kind:       "Component",
+				Name:       "my-component",
+				UID:        "5d1ad2c5-7c06-11e9-8f9e-2a86e4085a59",
+				Controller: &isController,
+			}},
+		},
+	}
+	unrelatedBC := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unrelated",
+			Namespace: Namespace,
+		},
+	}
+	mapper := newBuildMapper(fake.NewFakeClient(controlledBC, unrelatedBC))
+	newBuild := func(buildConfig string) *buildv1.Build {
+		build := &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      buildConfig + "-1",
+				Namespace: Namespace,
+				Labels:    map[string]string{},
+			},
+		}
+		if buildConfig != "" {
+			build.Labels[buildv1.BuildConfigLabel] = buildConfig
+		}
+		return build
+	}
+
+	t.Run("build of a controlled BuildConfig reconciles its Component", func(t *testing.T) {
+		build := newBuild(Name)
+		requests := mapper(handler.MapObject{Meta: build, Object: build})
+		require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "my-component", Namespace: Namespace}}}, requests)
+	})
+
+	t.Run("build of an unrelated BuildConfig does not reconcile any Component", func(t *testing.T) {
+		build := newBuild(unrelatedBC.Name)
+		require.Empty(t, mapper(handler.MapObject{Meta: build, Object: build}))
+	})
+
+	t.Run("build of a missing BuildConfig does not reconcile any Component", func(t *testing.T) {
+		build := newBuild("missing")
+		require.Empty(t, mapper(handler.MapObject{Meta: build, Object: build}))
+	})
+
+	t.Run("build without BuildConfig does not reconcile any Component", func(t *testing.T) {
+		build := newBuild("")
+		require.Empty(t, mapper(handler.MapObject{Meta: build, Object: build}))
+	})
+}
+
+func TestBuildChanges(t *testing.T) {
+	require.NoError(t, buildv1.AddToScheme(scheme.Scheme))
+	isController := true
+	controlledBC := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      Name,
+			Namespace: Namespace,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: devconsoleapi.SchemeGroupVersion.String(),
+				Kind:       "Component",
+				Name:       "my-component",
+				UID:        "5d1ad2c5-7c06-11e9-8f9e-2a86e4085a59",
+				Controller: &isController,
+			}},
+		},
+	}
+	unrelatedBC := &buildv1.BuildConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unrelated",
+			Namespace: Namespace,
+		},
+	}
+	mapper := &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: newBuildMapper(fake.NewFakeClient(controlledBC, unrelatedBC)),
+	}
+	newBuild := func(buildConfig, resourceVersion string) *buildv1.Build {
+		build := &buildv1.Build{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            buildConfig + "-1",
+				Namespace:       Namespace,
+				ResourceVersion: resourceVersion,
+				Labels:          map[string]string{},
+			},
+		}
+		if buildConfig != "" {
+			build.Labels[buildv1.BuildConfigLabel] = buildConfig
+		}
+		return build
+	}
+	// update sends the update of the Build through the predicate and the handler of its watch and returns the
+	// requests enqueued
+	update := func(old, updated *buildv1.Build) []reconcile.Request {
+		q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+		defer q.ShutDown()
+		e := event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: updated, ObjectNew: updated}
+		if buildChanges.Update(e) {
+			mapper.Update(e, q)
+		}
+		var requests []reconcile.Request
+		for q.Len() > 0 {
+			item, _ := q.Get()
+			requests = append(requests, item.(reconcile.Request))
+			q.Done(item)
+		}
+		return requests
+	}
+
+	t.Run("change to a Build of an unrelated BuildConfig does not reconcile any Component", func(t *testing.T) {
+		requests := update(newBuild(unrelatedBC.Name, "1"), newBuild(unrelatedBC.Name, "2"))
+		require.Empty(t, requests)
+	})
+
+	t.Run("change to a Build without BuildConfig is ignored", func(t *testing.T) {
+		old, updated := newBuild("", "1"), newBuild("", "2")
+		require.False(t, buildChanges.Update(event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: updated, ObjectNew: updated}))
+	})
+
+	t.Run("change to a Build of a controlled BuildConfig reconciles its Component", func(t *testing.T) {
+		requests := update(newBuild(Name, "1"), newBuild(Name, "2"))
+		require.Equal(t, []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "my-component", Namespace: Namespace}}}, requests)
+	})
+
+	t.Run("resync of a Build of a controlled BuildConfig is ignored", func(t *testing.T) {
+		requests := update(newBuild(Name, "1"), newBuild(Name, "1"))
+		require.Empty(t, requests)
+	})
+
+	t.Run("creation and deletion of a Build without BuildConfig are ignored", func(t *testing.T) {
+		build := newBuild("", "1")
+		require.False(t, buildChanges.Create(event.CreateEvent{Meta: build, Object: build}))
+		require.False(t, buildChanges.Delete(event.DeleteEvent{Meta: build, Object: build}))
+	})
+}
+
+func TestComponentOwnedChanges(t *testing.T) {
+	newImageStream := func(resourceVersion string, owned bool) *imagev1.ImageStream {
+		is := &imagev1.ImageStream{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "nodejs",
+				Namespace:       Namespace,
+				ResourceVersion: resourceVersion,
+			},
+		}
+		if owned {
+			// builder image streams are shared by the Components, which do not control them
+			is.OwnerReferences = []metav1.OwnerReference{{
+				APIVersion: devconsoleapi.SchemeGroupVersion.String(),
+				Kind:       "Component",
+				Name:       "my-component",
+				UID:        "5d1ad2c5-7c06-11e9-8f9e-2a86e4085a59",
+			}}
+		}
+		return is
+	}
+	update := func(old, updated *imagev1.ImageStream) event.UpdateEvent {
+		return event.UpdateEvent{MetaOld: old, ObjectOld: old, MetaNew: updated, ObjectNew: updated}
+	}
+
+	t.Run("change to an owned ImageStream is not ignored", func(t *testing.T) {
+		require.True(t, componentOwnedChanges.Update(update(newImageStream("1", true), newImageStream("2", true))))
+		require.False(t, componentControlledChanges.Update(update(newImageStream("1", true), newImageStream("2", true))), "builder image stream is not controlled")
+	})
+
+	t.Run("change to an unrelated ImageStream is ignored", func(t *testing.T) {
+		require.False(t, componentOwnedChanges.Update(update(newImageStream("1", false), newImageStream("2", false))))
+	})
+
+	t.Run("resync of an owned ImageStream is ignored", func(t *testing.T) {
+		require.False(t, componentOwnedChanges.Update(update(newImageStream("1", true), newImageStream("1", true))))
+	})
+}
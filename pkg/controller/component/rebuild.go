@@ -0,0 +1,57 @@
+package component
+
+import (
+	"fmt"
+
+	buildv1 "github.com/openshift/api/build/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Annotations of the Component requesting a new build or a new rollout. Each new value is handled once, the last
+// handled ones are recorded in the status of the Component.
+const (
+	// rebuildAnnotation starts a new build of the Component when its value changes.
+	rebuildAnnotation = "devconsole.openshift.io/rebuild"
+	// redeployAnnotation rolls the Component out again when its value changes. It is copied to the pod template, so
+	// that the DeploymentConfig, the Deployment or the Knative Service creates new pods.
+	redeployAnnotation = "devconsole.openshift.io/redeploy"
+)
+
+// isRebuildRequested returns true when the rebuild annotation of the Component has a value not handled yet.
+func isRebuildRequested(cp *devconsoleapi.Component) bool {
+	value := cp.Annotations[rebuildAnnotation]
+	return value != "" && value != cp.Status.ObservedRebuild
+}
+
+// reconcileRebuild starts a new build of the BuildConfig through its instantiate subresource when a rebuild is
+// requested. A BuildConfig which has not built yet is already building the latest commit, no build is started.
+func (r *ReconcileComponent) reconcileRebuild(cp *devconsoleapi.Component, bc *buildv1.BuildConfig) error {
+	if !isRebuildRequested(cp) {
+		return nil
+	}
+	value := cp.Annotations[rebuildAnnotation]
+	if bc.Status.LastVersion > 0 {
+		log.Info("💡💡  Starting a new Build 💡💡", "BuildConfig.Namespace", bc.Namespace, "BuildConfig.Name", bc.Name)
+		build, err := r.buildClient.BuildConfigs(bc.Namespace).Instantiate(bc.Name, &buildv1.BuildRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: bc.Name},
+			TriggeredBy: []buildv1.BuildTriggerCause{{
+				Message: fmt.Sprintf("Rebuild %s requested by annotation %s", value, rebuildAnnotation),
+			}},
+		})
+		if err != nil {
+			log.Error(err, "** Build instantiation fails **")
+			return err
+		}
+		log.Info("** Build started", "Build.Namespace", build.Namespace, "Build.Name", build.Name)
+	}
+	cp.Status.ObservedRebuild = value
+	return nil
+}
+
+// observeRedeploy records the redeploy annotation of the Component, copied to its pod template, as handled.
+func observeRedeploy(cp *devconsoleapi.Component) {
+	cp.Status.ObservedRedeploy = cp.Annotations[redeployAnnotation]
+}
@@ -0,0 +1,213 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "github.com/openshift/api/apps/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Types of the strategy rolling out a new version of a Component.
+const (
+	// DeploymentStrategyRolling replaces the pods progressively, without downtime.
+	DeploymentStrategyRolling = "Rolling"
+	// DeploymentStrategyRecreate stops all the pods before starting the new ones, the default.
+	DeploymentStrategyRecreate = "Recreate"
+)
+
+// Annotations of the Component controlling its rollouts.
+const (
+	// pauseRolloutAnnotation pauses the rollouts of the Component when set to true, its changes are rolled out once it
+	// is removed.
+	pauseRolloutAnnotation = "devconsole.openshift.io/pause-rollout"
+	// rollbackToAnnotation rolls the Component back to the pods of a previous version, the latest version of the
+	// DeploymentConfig or the revision of the Deployment. New images are not rolled out until it is removed.
+	rollbackToAnnotation = "devconsole.openshift.io/rollback-to"
+)
+
+// deploymentRevisionAnnotation is the revision of a Deployment and of its ReplicaSets.
+const deploymentRevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// Defaults of the rollout parameters, the ones set by the API server so that the live strategy does not drift from
+// the desired one.
+var (
+	defaultMaxSurge              = intstr.FromString("25%")
+	defaultMaxUnavailable        = intstr.FromString("25%")
+	defaultRolloutTimeoutSeconds = int64(600)
+	defaultRollingPeriodSeconds  = int64(1)
+)
+
+// deploymentStrategyOf returns the type of the strategy rolling out the Component, Recreate unless Rolling is
+// selected. Components with ReadWriteOnce volumes are always recreated, the pods of the new version could not mount
+// them on another node before the old ones stop.
+func deploymentStrategyOf(cp *devconsoleapi.Component) string {
+	if hasReadWriteOnceVolume(cp) {
+		return DeploymentStrategyRecreate
+	}
+	if cp.Spec.DeploymentStrategy != nil && cp.Spec.DeploymentStrategy.Type == DeploymentStrategyRolling {
+		return DeploymentStrategyRolling
+	}
+	return DeploymentStrategyRecreate
+}
+
+// rolloutParams returns the max surge, max unavailable and timeout of the rollouts of the Component, with their
+// defaults.
+func rolloutParams(cp *devconsoleapi.Component) (maxSurge, maxUnavailable intstr.IntOrString, timeoutSeconds int64) {
+	maxSurge, maxUnavailable, timeoutSeconds = defaultMaxSurge, defaultMaxUnavailable, defaultRolloutTimeoutSeconds
+	strategy := cp.Spec.DeploymentStrategy
+	if strategy == nil {
+		return
+	}
+	if strategy.MaxSurge != nil {
+		maxSurge = *strategy.MaxSurge
+	}
+	if strategy.MaxUnavailable != nil {
+		maxUnavailable = *strategy.MaxUnavailable
+	}
+	if strategy.TimeoutSeconds != nil {
+		timeoutSeconds = *strategy.TimeoutSeconds
+	}
+	return
+}
+
+// newDeploymentConfigStrategy returns the strategy of the DeploymentConfig of the Component.
+func newDeploymentConfigStrategy(cp *devconsoleapi.Component) v1.DeploymentStrategy {
+	maxSurge, maxUnavailable, timeoutSeconds := rolloutParams(cp)
+	if deploymentStrategyOf(cp) == DeploymentStrategyRecreate {
+		return v1.DeploymentStrategy{
+			Type: v1.DeploymentStrategyTypeRecreate,
+			RecreateParams: &v1.RecreateDeploymentStrategyParams{
+				TimeoutSeconds: &timeoutSeconds,
+			},
+		}
+	}
+	updatePeriodSeconds, intervalSeconds := defaultRollingPeriodSeconds, defaultRollingPeriodSeconds
+	return v1.DeploymentStrategy{
+		Type: v1.DeploymentStrategyTypeRolling,
+		RollingParams: &v1.RollingDeploymentStrategyParams{
+			UpdatePeriodSeconds: &updatePeriodSeconds,
+			IntervalSeconds:     &intervalSeconds,
+			TimeoutSeconds:      &timeoutSeconds,
+			MaxSurge:            &maxSurge,
+			MaxUnavailable:      &maxUnavailable,
+		},
+	}
+}
+
+// newDeploymentStrategy returns the strategy of the Deployment of the Component and the deadline of its rollouts.
+func newDeploymentStrategy(cp *devconsoleapi.Component) (appsv1.DeploymentStrategy, int32) {
+	maxSurge, maxUnavailable, timeoutSeconds := rolloutParams(cp)
+	if deploymentStrategyOf(cp) == DeploymentStrategyRecreate {
+		return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}, int32(timeoutSeconds)
+	}
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &maxSurge,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}, int32(timeoutSeconds)
+}
+
+// isRolloutPaused returns true when the rollouts of the Component are paused by its annotation.
+func isRolloutPaused(cp *devconsoleapi.Component) bool {
+	paused, _ := strconv.ParseBool(cp.Annotations[pauseRolloutAnnotation])
+	return paused
+}
+
+// GetRollbackPodSpec returns the pod spec of the version the Component is rolled back to by its annotation, from the
+// ReplicationController of the DeploymentConfig or the ReplicaSet of the Deployment running this version, or nil when
+// the Component is not rolled back. It returns false, with the DeploymentAvailable condition set, when the version is
+// invalid or not found.
+func (r *ReconcileComponent) GetRollbackPodSpec(cp *devconsoleapi.Component) (*corev1.PodSpec, bool, error) {
+	value, ok := cp.Annotations[rollbackToAnnotation]
+	if !ok {
+		return nil, true, nil
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 1 {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonRollbackInvalid, fmt.Sprintf("version %q of annotation %s is not a positive number", value, rollbackToAnnotation))
+		return nil, false, nil
+	}
+	if deploymentKindOf(cp) == DeploymentKindDeployment {
+		rsList := &appsv1.ReplicaSetList{}
+		if err := r.client.List(context.TODO(), &client.ListOptions{Namespace: cp.Namespace}, rsList); err != nil {
+			return nil, false, err
+		}
+		for _, rs := range rsList.Items {
+			owner := metav1.GetControllerOf(&rs)
+			if owner != nil && owner.Kind == DeploymentKindDeployment && owner.Name == cp.Name && deploymentRevision(rs.Annotations) == version {
+				return rs.Spec.Template.Spec.DeepCopy(), true, nil
+			}
+		}
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonRollbackInvalid, fmt.Sprintf("revision %d of Deployment %s is not found", version, cp.Name))
+		return nil, false, nil
+	}
+	rc := &corev1.ReplicationController{}
+	err = r.client.Get(context.TODO(), types.NamespacedName{Name: fmt.Sprintf("%s-%d", cp.Name, version), Namespace: cp.Namespace}, rc)
+	if errors.IsNotFound(err) || (err == nil && rc.Spec.Template == nil) {
+		setCondition(cp, ConditionDeploymentAvailable, corev1.ConditionFalse, ReasonRollbackInvalid, fmt.Sprintf("version %d of DeploymentConfig %s is not found", version, cp.Name))
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return rc.Spec.Template.Spec.DeepCopy(), true, nil
+}
+
+// rollBackDeploymentConfig runs the pods of a previous version in the DeploymentConfig, its image change trigger is
+// disabled so that the latest image is not rolled out again.
+func rollBackDeploymentConfig(dc *v1.DeploymentConfig, podSpec *corev1.PodSpec) {
+	dc.Spec.Template.Spec = *podSpec
+	for i := range dc.Spec.Triggers {
+		if dc.Spec.Triggers[i].ImageChangeParams != nil {
+			dc.Spec.Triggers[i].ImageChangeParams.Automatic = false
+		}
+	}
+}
+
+// rollBackDeployment runs the pods of a previous revision in the Deployment, its image trigger is paused so that the
+// latest image is not rolled out again.
+func rollBackDeployment(d *appsv1.Deployment, output string, podSpec *corev1.PodSpec) {
+	d.Spec.Template.Spec = *podSpec
+	d.Annotations[imageTriggersAnnotation] = newImageTriggers(output+":latest", output, true)
+}
+
+// rollBackImages sets the images of the previous version on the live containers, which keep the images resolved by
+// the triggers otherwise.
+func rollBackImages(found *corev1.PodSpec, podSpec *corev1.PodSpec) bool {
+	updated := false
+	for _, container := range podSpec.Containers {
+		foundContainer := findContainer(found.Containers, container.Name)
+		if foundContainer != nil && foundContainer.Image != container.Image {
+			foundContainer.Image = container.Image
+			updated = true
+		}
+	}
+	return updated
+}
+
+// observeRollout reports the latest version of the deployment of the Component and its ready replicas.
+func observeRollout(cp *devconsoleapi.Component, latestVersion int64, readyReplicas int32) {
+	cp.Status.LatestVersion = latestVersion
+	cp.Status.ReadyReplicas = readyReplicas
+}
+
+// deploymentRevision returns the revision annotated on a Deployment or a ReplicaSet, 0 before the first rollout.
+func deploymentRevision(annotations map[string]string) int64 {
+	revision, _ := strconv.ParseInt(annotations[deploymentRevisionAnnotation], 10, 64)
+	return revision
+}
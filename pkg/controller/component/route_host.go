@@ -0,0 +1,94 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	routev1 "github.com/openshift/api/route/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// routerHostClaimedReason is the reason of the Admitted condition set by the router on a Route whose host is already
+// claimed by an older Route, possibly of another namespace.
+const routerHostClaimedReason = "HostAlreadyClaimed"
+
+// validateRouteHost checks the host, path and wildcard policy of the Component's Route.
+func validateRouteHost(cp *devconsoleapi.Component) error {
+	if cp.Spec.Path != "" && !strings.HasPrefix(cp.Spec.Path, "/") {
+		return fmt.Errorf("path %s of the route must start with /", cp.Spec.Path)
+	}
+	if cp.Spec.Path != "" && isSecuredRoute(cp) && cp.Spec.TLS.Termination == string(routev1.TLSTerminationPassthrough) {
+		return fmt.Errorf("path %s is not supported with passthrough termination", cp.Spec.Path)
+	}
+	switch routev1.WildcardPolicyType(cp.Spec.WildcardPolicy) {
+	case "", routev1.WildcardPolicyNone:
+	case routev1.WildcardPolicySubdomain:
+		if cp.Spec.Host == "" {
+			return fmt.Errorf("wildcard policy Subdomain requires a host")
+		}
+	default:
+		return fmt.Errorf("wildcard policy %s is not supported, use one of None or Subdomain", cp.Spec.WildcardPolicy)
+	}
+	return nil
+}
+
+// routeDomain returns the domain claimed by a Route with the Subdomain wildcard policy, its host without the first
+// label, or an empty string for other Routes.
+func routeDomain(route *routev1.Route) string {
+	if route.Spec.WildcardPolicy != routev1.WildcardPolicySubdomain {
+		return ""
+	}
+	if i := strings.Index(route.Spec.Host, "."); i >= 0 {
+		return route.Spec.Host[i+1:]
+	}
+	return ""
+}
+
+// routesConflict returns true when both Routes claim the same host and path, or the same wildcard domain.
+func routesConflict(route, other *routev1.Route) bool {
+	if route.Spec.Host == "" || other.Spec.Host == "" {
+		return false
+	}
+	if domain := routeDomain(route); domain != "" && domain == routeDomain(other) {
+		return true
+	}
+	return route.Spec.Host == other.Spec.Host && route.Spec.Path == other.Spec.Path
+}
+
+// findConflictingRoute returns the Route of the namespace, not controlled by the Component, which already claims the
+// host and path of the given Route, or nil. Conflicts with Routes of other namespaces are reported by the router.
+func (r *ReconcileComponent) findConflictingRoute(cp *devconsoleapi.Component, route *routev1.Route) (*routev1.Route, error) {
+	if route.Spec.Host == "" {
+		return nil, nil
+	}
+	routeList := &routev1.RouteList{}
+	if err := r.client.List(context.TODO(), &client.ListOptions{Namespace: cp.Namespace}, routeList); err != nil {
+		log.Error(err, "** failed to list routes **")
+		return nil, err
+	}
+	for i := range routeList.Items {
+		other := &routeList.Items[i]
+		if metav1.IsControlledBy(other, cp) {
+			continue
+		}
+		if routesConflict(route, other) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// routeURL returns the URL of the Route from the host admitted by the given router ingress.
+func routeURL(route *routev1.Route, ingress routev1.RouteIngress) string {
+	scheme := "http"
+	if route.Spec.TLS != nil {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, ingress.Host, route.Spec.Path)
+}
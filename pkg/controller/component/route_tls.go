@@ -0,0 +1,105 @@
+package component
+
+import (
+	"context"
+	"fmt"
+
+	routev1 "github.com/openshift/api/route/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Keys of the TLS Secret of a Component holding the CA certificates, next to the tls.crt and tls.key ones.
+const (
+	tlsCACertKey            = "ca.crt"
+	tlsDestinationCACertKey = "destination-ca.crt"
+)
+
+// isSecuredRoute returns true when the Route of the Component terminates TLS.
+func isSecuredRoute(cp *devconsoleapi.Component) bool {
+	return cp.Spec.TLS != nil && cp.Spec.TLS.Termination != ""
+}
+
+// tlsSecretName returns the name of the Secret holding the certificate and key of the Route, if any.
+func tlsSecretName(cp *devconsoleapi.Component) string {
+	if !isSecuredRoute(cp) || cp.Spec.TLS.SecretRef == nil {
+		return ""
+	}
+	return cp.Spec.TLS.SecretRef.Name
+}
+
+// validateRouteTLS checks the termination and the insecure edge termination policy of the Component's Route.
+func validateRouteTLS(tls *devconsoleapi.ComponentTLS) error {
+	termination := routev1.TLSTerminationType(tls.Termination)
+	switch termination {
+	case routev1.TLSTerminationEdge, routev1.TLSTerminationPassthrough, routev1.TLSTerminationReencrypt:
+	default:
+		return fmt.Errorf("TLS termination %s is not supported, use one of edge, passthrough or reencrypt", tls.Termination)
+	}
+	policy := routev1.InsecureEdgeTerminationPolicyType(tls.InsecureEdgeTerminationPolicy)
+	switch policy {
+	case "", routev1.InsecureEdgeTerminationPolicyNone, routev1.InsecureEdgeTerminationPolicyRedirect:
+	case routev1.InsecureEdgeTerminationPolicyAllow:
+		if termination == routev1.TLSTerminationPassthrough {
+			return fmt.Errorf("insecure edge termination policy Allow is not supported with passthrough termination")
+		}
+	default:
+		return fmt.Errorf("insecure edge termination policy %s is not supported, use one of None, Allow or Redirect", tls.InsecureEdgeTerminationPolicy)
+	}
+	return nil
+}
+
+// newRouteTLS returns the TLS configuration of the Component's Route, the certificates being copied from the given
+// Secret. The router gets them from the passthrough Route's backend, no certificate is copied for it.
+func newRouteTLS(cp *devconsoleapi.Component, secret *corev1.Secret) *routev1.TLSConfig {
+	if !isSecuredRoute(cp) {
+		return nil
+	}
+	termination := routev1.TLSTerminationType(cp.Spec.TLS.Termination)
+	config := &routev1.TLSConfig{
+		Termination:                   termination,
+		InsecureEdgeTerminationPolicy: routev1.InsecureEdgeTerminationPolicyType(cp.Spec.TLS.InsecureEdgeTerminationPolicy),
+	}
+	if termination == routev1.TLSTerminationPassthrough || secret == nil {
+		return config
+	}
+	config.Certificate = string(secret.Data[corev1.TLSCertKey])
+	config.Key = string(secret.Data[corev1.TLSPrivateKeyKey])
+	config.CACertificate = string(secret.Data[tlsCACertKey])
+	if termination == routev1.TLSTerminationReencrypt {
+		config.DestinationCACertificate = string(secret.Data[tlsDestinationCACertKey])
+	}
+	return config
+}
+
+// GetRouteTLS returns the TLS configuration of the Component's Route, nil for a plain HTTP Route. It returns false,
+// with the RouteAdmitted condition set, when the configuration is invalid or its Secret is not found yet.
+func (r *ReconcileComponent) GetRouteTLS(cp *devconsoleapi.Component) (*routev1.TLSConfig, bool, error) {
+	if !isSecuredRoute(cp) {
+		return nil, true, nil
+	}
+	if err := validateRouteTLS(cp.Spec.TLS); err != nil {
+		setCondition(cp, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonTLSConfigInvalid, err.Error())
+		return nil, false, nil
+	}
+	name := tlsSecretName(cp)
+	if name == "" {
+		return newRouteTLS(cp, nil), true, nil
+	}
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cp.Namespace}, secret)
+	if errors.IsNotFound(err) {
+		log.Info("** TLS Secret NOT found ", "Secret.Namespace", cp.Namespace, "Secret.Name", name)
+		setCondition(cp, ConditionRouteAdmitted, corev1.ConditionFalse, ReasonTLSSecretNotFound, fmt.Sprintf("TLS Secret %s not found", name))
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return newRouteTLS(cp, secret), true, nil
+}
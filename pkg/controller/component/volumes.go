@@ -0,0 +1,184 @@
+package component
+
+import (
+	"context"
+	"fmt"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"github.com/redhat-developer/devconsole-operator/pkg/resource"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// volumeClaimName returns the name of the PersistentVolumeClaim of the Component's volume.
+func volumeClaimName(cp *devconsoleapi.Component, volume devconsoleapi.ComponentVolume) string {
+	return fmt.Sprintf("%s-%s", cp.Name, volume.Name)
+}
+
+// volumeAccessMode returns the access mode of the volume, ReadWriteOnce by default.
+func volumeAccessMode(volume devconsoleapi.ComponentVolume) corev1.PersistentVolumeAccessMode {
+	if volume.AccessMode == "" {
+		return corev1.ReadWriteOnce
+	}
+	return volume.AccessMode
+}
+
+// hasReadWriteOnceVolume returns true when a volume of the Component can only be mounted by the pods of a single node,
+// the pods of two versions cannot run side by side during a rolling rollout.
+func hasReadWriteOnceVolume(cp *devconsoleapi.Component) bool {
+	for _, volume := range cp.Spec.Volumes {
+		if volumeAccessMode(volume) == corev1.ReadWriteOnce {
+			return true
+		}
+	}
+	return false
+}
+
+// validateVolumes checks the volumes of the Component, which are not supported by Knative Services.
+func validateVolumes(cp *devconsoleapi.Component) error {
+	if len(cp.Spec.Volumes) == 0 {
+		return nil
+	}
+	if deploymentKindOf(cp) == DeploymentKindKnativeService {
+		return fmt.Errorf("volumes are not supported by Knative Services")
+	}
+	names := make(map[string]bool)
+	mountPaths := make(map[string]bool)
+	for _, volume := range cp.Spec.Volumes {
+		if volume.Name == "" || volume.MountPath == "" {
+			return fmt.Errorf("volumes must have a name and a mount path")
+		}
+		if names[volume.Name] || mountPaths[volume.MountPath] {
+			return fmt.Errorf("volume %s is declared twice or mounted on the path of another volume", volume.Name)
+		}
+		names[volume.Name] = true
+		mountPaths[volume.MountPath] = true
+		if volume.Size.Sign() <= 0 {
+			return fmt.Errorf("size of volume %s must be positive", volume.Name)
+		}
+		switch volumeAccessMode(volume) {
+		case corev1.ReadWriteOnce, corev1.ReadOnlyMany, corev1.ReadWriteMany:
+		default:
+			return fmt.Errorf("access mode %s of volume %s is not supported", volume.AccessMode, volume.Name)
+		}
+	}
+	return nil
+}
+
+// newPersistentVolumeClaim returns the PersistentVolumeClaim of the Component's volume.
+func newPersistentVolumeClaim(cp *devconsoleapi.Component, volume devconsoleapi.ComponentVolume) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        volumeClaimName(cp, volume),
+			Namespace:   cp.Namespace,
+			Labels:      labelsForComponent(cp),
+			Annotations: resource.GetAnnotationsForCR(cp),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes:      []corev1.PersistentVolumeAccessMode{volumeAccessMode(volume)},
+			StorageClassName: volume.StorageClass,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: volume.Size},
+			},
+		},
+	}
+}
+
+// newPodVolumes returns the volumes of the pods of the Component and their mounts in its container.
+func newPodVolumes(cp *devconsoleapi.Component) ([]corev1.Volume, []corev1.VolumeMount) {
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+	for _, volume := range cp.Spec.Volumes {
+		volumes = append(volumes, corev1.Volume{
+			Name: volume.Name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: volumeClaimName(cp, volume),
+					ReadOnly:  volumeAccessMode(volume) == corev1.ReadOnlyMany,
+				},
+			},
+		})
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: volume.MountPath,
+			ReadOnly:  volumeAccessMode(volume) == corev1.ReadOnlyMany,
+		})
+	}
+	return volumes, mounts
+}
+
+// reconcileVolumes creates the PersistentVolumeClaims of the Component's volumes and deletes the ones of the volumes
+// removed from its spec.
+func (r *ReconcileComponent) reconcileVolumes(cp *devconsoleapi.Component) error {
+	claimNames := make(map[string]bool)
+	for _, volume := range cp.Spec.Volumes {
+		pvc, err := r.CreatePersistentVolumeClaim(cp, volume)
+		if err != nil {
+			return err
+		}
+		claimNames[pvc.Name] = true
+	}
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	opts := &client.ListOptions{
+		Namespace:     cp.Namespace,
+		LabelSelector: labels.SelectorFromSet(map[string]string{componentUIDLabel: string(cp.UID)}),
+	}
+	if err := r.client.List(context.TODO(), opts, pvcList); err != nil {
+		log.Error(err, "failed to list existing PersistentVolumeClaims")
+		return err
+	}
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if claimNames[pvc.Name] || !metav1.IsControlledBy(pvc, cp) {
+			continue
+		}
+		log.Info("💡💡  Deleting PersistentVolumeClaim of a removed volume 💡💡", "PersistentVolumeClaim.Namespace", pvc.Namespace, "PersistentVolumeClaim.Name", pvc.Name)
+		if err := r.delete(pvc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreatePersistentVolumeClaim creates the PersistentVolumeClaim of the Component's volume, or expands the live one
+// when the size of the volume grows. The other fields of a claim cannot be changed once it is created.
+func (r *ReconcileComponent) CreatePersistentVolumeClaim(cp *devconsoleapi.Component, volume devconsoleapi.ComponentVolume) (*corev1.PersistentVolumeClaim, error) {
+	pvc := newPersistentVolumeClaim(cp, volume)
+	if err := controllerutil.SetControllerReference(cp, pvc, r.scheme); err != nil {
+		log.Error(err, "** Setting owner reference fails **")
+		return nil, err
+	}
+	foundPvc := &corev1.PersistentVolumeClaim{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: pvc.Name, Namespace: pvc.Namespace}, foundPvc)
+	if err == nil {
+		if !updatePersistentVolumeClaim(foundPvc, pvc) {
+			log.Info("** Skip Updating PersistentVolumeClaim: Already up to date", "PersistentVolumeClaim.Namespace", foundPvc.Namespace, "PersistentVolumeClaim.Name", foundPvc.Name)
+			return foundPvc, nil
+		}
+		log.Info("💡💡  Updating PersistentVolumeClaim 💡💡", "PersistentVolumeClaim.Namespace", foundPvc.Namespace, "PersistentVolumeClaim.Name", foundPvc.Name)
+		if err := r.client.Update(context.TODO(), foundPvc); err != nil {
+			log.Error(err, "** PersistentVolumeClaim update fails **")
+			return nil, err
+		}
+		return foundPvc, nil
+	}
+	if errors.IsNotFound(err) {
+		log.Info("💡💡  Creating a new PersistentVolumeClaim 💡💡", "PersistentVolumeClaim.Namespace", pvc.Namespace, "PersistentVolumeClaim.Name", pvc.Name)
+		err := r.client.Create(context.TODO(), pvc)
+		if err != nil && !errors.IsAlreadyExists(err) {
+			log.Error(err, "** PersistentVolumeClaim creation fails **")
+			return nil, err
+		}
+		return pvc, nil
+	}
+	return nil, err
+}
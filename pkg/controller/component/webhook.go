@@ -0,0 +1,187 @@
+package component
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	buildv1 "github.com/openshift/api/build/v1"
+
+	devconsoleapi "github.com/redhat-developer/devconsole-api/pkg/apis/devconsole/v1alpha1"
+
+	"github.com/redhat-developer/devconsole-operator/pkg/resource"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// apiServerURLEnvVar sets the public URL of the API server when it differs from the one the operator connects to.
+const apiServerURLEnvVar = "API_SERVER_URL"
+
+// webhookSecretKey is the key of the webhook secret in the Secret referenced by the webhook triggers of a
+// BuildConfig.
+const webhookSecretKey = "WebHookSecretKey"
+
+// Flavors of the git providers of a GitSource, each one has its own webhook payload.
+const (
+	flavorGitHub    = "github"
+	flavorGitLab    = "gitlab"
+	flavorBitbucket = "bitbucket"
+	flavorGeneric   = "generic"
+)
+
+// webhookSecretName returns the name of the Secret holding the webhook secret of the Component's BuildConfig.
+func webhookSecretName(cp *devconsoleapi.Component) string {
+	return cp.Name + "-webhook"
+}
+
+// gitFlavorOf returns the flavor of the git provider of the GitSource, the one of its spec or else the one guessed
+// from the host of its URL, generic by default.
+func gitFlavorOf(gitSource *devconsoleapi.GitSource) string {
+	flavor := strings.ToLower(gitSource.Spec.Flavor)
+	switch flavor {
+	case flavorGitHub, flavorGitLab, flavorBitbucket, flavorGeneric:
+		return flavor
+	case "":
+		u, err := url.Parse(gitSource.Spec.URL)
+		if err != nil {
+			return flavorGeneric
+		}
+		for _, known := range []string{flavorGitHub, flavorGitLab, flavorBitbucket} {
+			if strings.Contains(strings.ToLower(u.Hostname()), known) {
+				return known
+			}
+		}
+	}
+	return flavorGeneric
+}
+
+// newWebhookTrigger returns the webhook trigger of the BuildConfig matching the flavor of the GitSource.
+func newWebhookTrigger(cp *devconsoleapi.Component, gitSource *devconsoleapi.GitSource) buildv1.BuildTriggerPolicy {
+	webhook := &buildv1.WebHookTrigger{
+		SecretReference: &buildv1.SecretLocalReference{Name: webhookSecretName(cp)},
+	}
+	switch gitFlavorOf(gitSource) {
+	case flavorGitHub:
+		return buildv1.BuildTriggerPolicy{Type: buildv1.GitHubWebHookBuildTriggerType, GitHubWebHook: webhook}
+	case flavorGitLab:
+		return buildv1.BuildTriggerPolicy{Type: buildv1.GitLabWebHookBuildTriggerType, GitLabWebHook: webhook}
+	case flavorBitbucket:
+		return buildv1.BuildTriggerPolicy{Type: buildv1.BitbucketWebHookBuildTriggerType, BitbucketWebHook: webhook}
+	}
+	return buildv1.BuildTriggerPolicy{Type: buildv1.GenericWebHookBuildTriggerType, GenericWebHook: webhook}
+}
+
+// newWebhookSecret returns the Secret holding a random webhook secret for the Component's BuildConfig.
+func newWebhookSecret(cp *devconsoleapi.Component) (*corev1.Secret, error) {
+	value := make([]byte, 20)
+	if _, err := rand.Read(value); err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        webhookSecretName(cp),
+			Namespace:   cp.Namespace,
+			Labels:      labelsForComponent(cp),
+			Annotations: resource.GetAnnotationsForCR(cp),
+		},
+		Data: map[string][]byte{
+			webhookSecretKey: []byte(hex.EncodeToString(value)),
+		},
+	}, nil
+}
+
+// webhookURL returns the URL of the webhook of the BuildConfig, called by the git provider to start a build.
+func webhookURL(apiServerURL string, cp *devconsoleapi.Component, gitSource *devconsoleapi.GitSource, secret *corev1.Secret) string {
+	return fmt.Sprintf("%s/apis/build.openshift.io/v1/namespaces/%s/buildconfigs/%s/webhooks/%s/%s",
+		strings.TrimSuffix(apiServerURL, "/"), cp.Namespace, cp.Name, secret.Data[webhookSecretKey], gitFlavorOf(gitSource))
+}
+
+// reconcileWebhook creates the webhook Secret of the Component's BuildConfig and publishes the URL of its webhook in
+// the status of the Component. The webhook secret is generated once, the URL set in the git provider stays valid.
+func (r *ReconcileComponent) reconcileWebhook(cp *devconsoleapi.Component, gitSource *devconsoleapi.GitSource) error {
+	secret, err := r.CreateWebhookSecret(cp)
+	if err != nil {
+		cp.Status.WebhookURL = ""
+		return err
+	}
+	cp.Status.WebhookURL = webhookURL(r.apiServerURL, cp, gitSource, secret)
+	return nil
+}
+
+// CreateWebhookSecret creates the webhook Secret of the Component if it does not exist yet. A Secret of the same name
+// created by someone else is used as is when it holds a webhook secret, otherwise it is left untouched and reported
+// with a permanent error: the Component is reconciled again once the Secret changes.
+func (r *ReconcileComponent) CreateWebhookSecret(cp *devconsoleapi.Component) (*corev1.Secret, error) {
+	foundSecret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: webhookSecretName(cp), Namespace: cp.Namespace}, foundSecret)
+	if err == nil {
+		if len(foundSecret.Data[webhookSecretKey]) > 0 {
+			log.Info("** Skip Updating webhook Secret: Already created", "Secret.Namespace", foundSecret.Namespace, "Secret.Name", foundSecret.Name)
+			return foundSecret, nil
+		}
+		if !metav1.IsControlledBy(foundSecret, cp) {
+			err := fmt.Errorf("secret %s is not created by the component and has no %s key", foundSecret.Name, webhookSecretKey)
+			setCondition(cp, ConditionBuildSucceeded, corev1.ConditionFalse, ReasonWebhookSecretInvalid, err.Error())
+			return nil, newPermanentError(err)
+		}
+		// the webhook secret removed from the Secret of the Component is generated again
+		secret, err := newWebhookSecret(cp)
+		if err != nil {
+			return nil, err
+		}
+		if foundSecret.Data == nil {
+			foundSecret.Data = make(map[string][]byte)
+		}
+		foundSecret.Data[webhookSecretKey] = secret.Data[webhookSecretKey]
+		log.Info("💡💡  Updating webhook Secret 💡💡", "Secret.Namespace", foundSecret.Namespace, "Secret.Name", foundSecret.Name)
+		if err := r.client.Update(context.TODO(), foundSecret); err != nil {
+			log.Error(err, "** Webhook Secret update fails **")
+			return nil, err
+		}
+		return foundSecret, nil
+	}
+	if !errors.IsNotFound(err) {
+		return nil, err
+	}
+	secret, err := newWebhookSecret(cp)
+	if err != nil {
+		return nil, err
+	}
+	if err := controllerutil.SetControllerReference(cp, secret, r.scheme); err != nil {
+		log.Error(err, "** Setting owner reference fails **")
+		return nil, err
+	}
+	log.Info("💡💡  Creating a new webhook Secret 💡💡", "Secret.Namespace", secret.Namespace, "Secret.Name", secret.Name)
+	if err := r.client.Create(context.TODO(), secret); err != nil {
+		log.Error(err, "** Webhook Secret creation fails **")
+		return nil, err
+	}
+	return secret, nil
+}
+
+// deleteWebhook deletes the webhook Secret of a Component which is not built anymore and removes its URL from its
+// status.
+func (r *ReconcileComponent) deleteWebhook(cp *devconsoleapi.Component) error {
+	cp.Status.WebhookURL = ""
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: webhookSecretName(cp), Namespace: cp.Namespace}, secret)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !metav1.IsControlledBy(secret, cp) {
+		return nil
+	}
+	return r.delete(secret)
+}